@@ -46,7 +46,7 @@ func (c *Client) CleanJobs(opts CleanJobsOptions) (result CleanJobsResult, err e
 	if status == common.EJobStatus.All() {
 		result.Count, err = jobsAdmin.DeleteAllJobFilesExceptCurrent(c.CurrentJobID)
 	} else {
-		resp := jobsAdmin.ListJobs(status)
+		resp := jobsAdmin.ListJobs(status, false)
 		if resp.ErrorMessage != "" {
 			return result, fmt.Errorf("failed to list jobs due to error: %s", resp.ErrorMessage)
 		}
@@ -29,7 +29,8 @@ import (
 )
 
 type ListJobsOptions struct {
-	WithStatus *common.JobStatus // Default: All
+	WithStatus       *common.JobStatus // Default: All
+	IncludeSummaries bool              // Also summarize each job's status and progress, at the cost of reading all of its plan files
 }
 
 type JobDetail struct {
@@ -41,12 +42,15 @@ type JobDetail struct {
 
 type ListJobsResponse struct {
 	Details []JobDetail
+	// JobSummaries is only filled in with IncludeSummaries, and is lightweight:
+	// it lacks the lists of failed and skipped transfers.
+	JobSummaries []common.ListJobSummaryResponse
 }
 
 func (c *Client) ListJobs(opts ListJobsOptions) (result ListJobsResponse, err error) {
 	status := common.IffNil(opts.WithStatus, common.EJobStatus.All())
 
-	resp := jobsAdmin.ListJobs(status)
+	resp := jobsAdmin.ListJobs(status, opts.IncludeSummaries)
 	if resp.ErrorMessage != "" {
 		return ListJobsResponse{}, fmt.Errorf("failed to list jobs due to error: %s", resp.ErrorMessage)
 	}
@@ -65,7 +69,8 @@ func (c *Client) ListJobs(opts ListJobsOptions) (result ListJobsResponse, err er
 	sortJobs(details)
 
 	return ListJobsResponse{
-		Details: details,
+		Details:      details,
+		JobSummaries: resp.JobSummaries,
 	}, nil
 }
 
@@ -1,10 +1,13 @@
 package azcopy
 
 import (
-	"github.com/Azure/azure-storage-azcopy/v10/common"
-	"github.com/stretchr/testify/assert"
+	"fmt"
 	"testing"
 	"time"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+	"github.com/Azure/azure-storage-azcopy/v10/ste"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSortJobs(t *testing.T) {
@@ -41,3 +44,46 @@ func TestSortJobs(t *testing.T) {
 	a.Equal(job1, jobsList[1])
 	a.Equal(job2, jobsList[2])
 }
+
+func TestListJobsIncludeSummaries(t *testing.T) {
+	a := assert.New(t)
+	// setup: a job with a single part, whose plan file is all ListJobs looks at
+	oldPlanFolder := common.AzcopyJobPlanFolder
+	common.AzcopyJobPlanFolder = t.TempDir()
+	defer func() { common.AzcopyJobPlanFolder = oldPlanFolder }()
+
+	jobID := common.NewJobID()
+	planFile := ste.JobPartPlanFileName(fmt.Sprintf(ste.JobPartPlanFileNameFormat, jobID.String(), 0, ste.DataSchemaVersion))
+	planFile.Create(common.CopyJobPartOrderRequest{
+		JobID:           jobID,
+		PartNum:         0,
+		IsFinalPart:     true,
+		FromTo:          common.EFromTo.LocalBlob(),
+		SourceRoot:      common.ResourceString{Value: "/tmp/source"},
+		DestinationRoot: common.ResourceString{Value: "https://account.blob.core.windows.net/container"},
+		Transfers: common.Transfers{
+			List:              []common.CopyTransfer{{Source: "file.txt", Destination: "file.txt", SourceSize: 10, EntityType: common.EEntityType.File()}},
+			TotalSizeInBytes:  10,
+			FileTransferCount: 1,
+		},
+	})
+
+	// act & verify: the cheap path leaves the summaries out
+	resp, err := (&Client{}).ListJobs(ListJobsOptions{})
+	a.NoError(err)
+	a.Len(resp.Details, 1)
+	a.Nil(resp.JobSummaries)
+
+	resp, err = (&Client{}).ListJobs(ListJobsOptions{IncludeSummaries: true})
+	a.NoError(err)
+	a.Len(resp.Details, 1)
+	a.Len(resp.JobSummaries, 1)
+	summary := resp.JobSummaries[0]
+	a.Equal(jobID, summary.JobID)
+	a.Equal(common.EJobStatus.InProgress(), summary.JobStatus)
+	a.True(summary.CompleteJobOrdered)
+	a.Equal(uint32(1), summary.TotalTransfers)
+	a.Equal(uint32(0), summary.TransfersCompleted)
+	a.Equal(float32(0), summary.PercentComplete)
+	a.Nil(summary.FailedTransfers)
+}
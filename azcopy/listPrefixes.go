@@ -0,0 +1,91 @@
+// Copyright © 2025 Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azcopy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/minio/minio-go"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+)
+
+// ListPrefixes lists the common prefixes directly below r.Prefix in the S3 bucket r.Source, i.e. its virtual directories,
+// as S3's ListObjectsV2 does when given a delimiter. The delimiter defaults to "/". The objects themselves aren't listed.
+func (c *Client) ListPrefixes(ctx context.Context, r common.ListPrefixesRequest) ([]string, error) {
+	u, err := url.Parse(r.Source)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the source: %w", err)
+	}
+	if !common.IsS3URL(*u) {
+		return nil, errors.New("prefixes can only be listed for S3 sources")
+	}
+	s3URLParts, err := common.NewS3URLParts(*u)
+	if err != nil {
+		return nil, err
+	}
+	if s3URLParts.BucketName == "" {
+		return nil, errors.New("the source must name a bucket to list the prefixes of")
+	}
+	delimiter := r.Delimiter
+	if delimiter == "" {
+		delimiter = common.AZCOPY_PATH_SEPARATOR_STRING
+	}
+
+	credType, _, err := GetCredentialTypeForLocation(ctx, common.ELocation.S3(), common.ResourceString{Value: r.Source}, true, common.CpkOptions{}, c.GetUserOAuthTokenManagerInstance())
+	if err != nil {
+		return nil, err
+	}
+	s3Client, err := common.CreateS3Client(ctx, common.CredentialInfo{
+		CredentialType: credType,
+		S3CredentialInfo: common.S3CredentialInfo{
+			Endpoint:  s3URLParts.Endpoint,
+			Region:    s3URLParts.Region,
+			PathStyle: s3URLParts.RequiresPathStyle(),
+		},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	core := minio.Core{Client: s3Client}
+	prefixes := []string{}
+	continuationToken := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := core.ListObjectsV2(s3URLParts.BucketName, r.Prefix, continuationToken, false, delimiter, 0, "")
+		if err != nil {
+			return nil, fmt.Errorf("cannot list prefixes, %w", err)
+		}
+		for _, prefix := range result.CommonPrefixes {
+			prefixes = append(prefixes, prefix.Prefix)
+		}
+		if !result.IsTruncated {
+			return prefixes, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
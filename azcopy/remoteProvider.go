@@ -179,7 +179,7 @@ func getDestinationServiceClient(ctx context.Context,
 		srcTokenCred = common.NewScopedCredential(sourceTc, srcCredType)
 	}
 
-	options := traverser.CreateClientOptions(common.AzcopyCurrentJobLogger, srcTokenCred, dstReauthTok)
+	options := traverser.CreateDestinationClientOptions(common.AzcopyCurrentJobLogger, srcTokenCred, dstReauthTok)
 
 	// Create Destination Client.
 	var azureFileSpecificOptions any
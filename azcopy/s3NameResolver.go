@@ -21,10 +21,9 @@
 package azcopy
 
 import (
-	"bytes"
 	"fmt"
-	"strconv"
-	"strings"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
 )
 
 // S3BucketNameToAzureResourcesResolver resolves s3 bucket name to Azure Blob container/ADLS Gen2 filesystem/File share.
@@ -99,65 +98,24 @@ func (s3Resolver *S3BucketNameToAzureResourcesResolver) ResolveName(bucketName s
 }
 
 func (s3Resolver *S3BucketNameToAzureResourcesResolver) resolveNewBucketNameInternal(orgBucketName string) {
-	// Check if the bucket name contains periods or consecutive hyphens, and try to resolve them.
-	hasPeriod := strings.Contains(orgBucketName, ".")
-	hasConsecutiveHyphen := strings.Contains(orgBucketName, "--")
-
-	if !hasPeriod && !hasConsecutiveHyphen {
-		// The name should be valid for Azure
+	// 1. Replace periods with hyphens, and consecutive hyphens with -[number]-, as common.ResolveS3BucketNameForAzure does.
+	// e.g.: bucket.name will be resolved as bucket-name, and b---name will be resolved as b-3-name
+	resolvedName, err := common.ResolveS3BucketNameForAzure(orgBucketName)
+	if err != nil {
+		s3Resolver.bucketNameResolvingMap[orgBucketName] = failToResolveMapValue
 		return
 	}
-
-	// Init resolved name as original bucket name
-	resolvedName := orgBucketName
-
-	// 1. Try to replace period with hyphen.
-	// Note: there should be no '.' adjacent to '-'.
-	if hasPeriod {
-		resolvedName = strings.Replace(orgBucketName, ".", "-", -1)
-	}
-
-	// 2. Try to replace consecutive hyphen with -[number]-.
-	// e.g.: bucket--name will be resolved as bucket-2-name, and b---name will be resolved as b-3-name
-	if hasConsecutiveHyphen {
-		var buffer bytes.Buffer
-		consecutiveHyphenCount := 0
-		for i := 0; i < len(resolvedName); i++ {
-			charAtI := resolvedName[i] // ASCII is enough for bucket name which contains lower-case characters, numbers, periods, and dashes.
-			if charAtI == '-' {
-				// the char is hyphen, adding consecutiveHyphenCount and continue
-				consecutiveHyphenCount++
-				continue
-			}
-
-			// Found byte that doesn't indicate '-'
-			if consecutiveHyphenCount == 0 {
-				// current char is non '-', and no preceding '-', directly write the char to buffer.
-				buffer.WriteByte(charAtI)
-			} else if consecutiveHyphenCount == 1 {
-				buffer.WriteString("-")
-				buffer.WriteByte(charAtI)
-			} else { // consecutiveHyphenCount > 1
-				buffer.WriteString("-")
-				buffer.WriteString(strconv.Itoa(consecutiveHyphenCount))
-				buffer.WriteString("-")
-				buffer.WriteByte(charAtI)
-			}
-			consecutiveHyphenCount = 0
-		}
-		// Please note S3's bucketname cannot use '-' as suffix, so don't need to handle the case where consecutiveHyphenCount is larger than 0.
-		if consecutiveHyphenCount > 0 {
-			panic("invalid state: consecutiveHyphenCount should not be greater than 0")
-		}
-		resolvedName = buffer.String()
+	if resolvedName == orgBucketName {
+		// The name should be valid for Azure
+		return
 	}
 
-	// 3. If there is naming collision, try to add suffix.
+	// 2. If there is naming collision, try to add suffix.
 	if s3Resolver.hasCollision(resolvedName) {
 		resolvedName = s3Resolver.addSuffix(resolvedName)
 	}
 
-	// 4. Validate if name is resolved correctly.
+	// 3. Validate if name is resolved correctly.
 	if !validateResolvedName(resolvedName) {
 		resolvedName = failToResolveMapValue
 	}
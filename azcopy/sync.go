@@ -179,7 +179,10 @@ func (c *Client) Sync(ctx context.Context, src, dest string, opts SyncOptions, h
 	}
 	// if we are in dryrun mode, we don't want to actually run the job, so return here
 	if s.opts.dryrun {
-		return SyncResult{}, nil
+		return SyncResult{
+			SourceFilesScanned:      s.spt.getSourceFilesScanned(),
+			DestinationFilesScanned: s.spt.getDestinationFilesScanned(),
+		}, nil
 	}
 
 	err = mgr.Wait()
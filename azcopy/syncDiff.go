@@ -0,0 +1,84 @@
+// Copyright © 2025 Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azcopy
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+	"github.com/Azure/azure-storage-azcopy/v10/traverser"
+)
+
+// SyncDiff compares src and dest exactly as Sync would, and reports what Sync would copy and delete,
+// without transferring or deleting anything.
+// Extra destination objects are always reported in ToDelete, regardless of opts.DeleteDestination.
+func (c *Client) SyncDiff(ctx context.Context, src, dest string, opts SyncOptions) (common.SyncDiffResult, error) {
+	var mu sync.Mutex
+	var result common.SyncDiffResult
+	var filesToCopy uint64
+
+	collectTransfers := func(order common.CopyJobPartOrderRequest) common.CopyJobPartOrderResponse {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, transfer := range order.Transfers.List {
+			relativePath := transfer.Source
+			if order.FromTo.From().IsRemote() {
+				if unescaped, err := url.PathUnescape(relativePath); err == nil {
+					relativePath = unescaped
+				}
+			}
+			result.ToCopy = append(result.ToCopy, relativePath)
+			if transfer.EntityType == common.EEntityType.File() {
+				filesToCopy++
+			}
+		}
+		return common.CopyJobPartOrderResponse{JobStarted: true}
+	}
+	collectDeletions := func(_ string, _ common.Location, object traverser.StoredObject) error {
+		mu.Lock()
+		defer mu.Unlock()
+		result.ToDelete = append(result.ToDelete, object.RelativePath)
+		return nil
+	}
+
+	opts.DeleteDestination = common.EDeleteDestination.True()
+	opts.SetInternalOptions(true, opts.deleteDestinationFileIfNecessary, opts.commandString, collectTransfers, collectDeletions)
+
+	syncResult, err := c.Sync(ctx, src, dest, opts, syncDiffHandler{})
+	if err != nil {
+		return common.SyncDiffResult{}, err
+	}
+
+	// the scan only counts files, so leave the folders out of the comparison
+	if syncResult.SourceFilesScanned > filesToCopy {
+		result.Unchanged = syncResult.SourceFilesScanned - filesToCopy
+	}
+	return result, nil
+}
+
+// syncDiffHandler ignores progress; nothing is transferred while diffing.
+type syncDiffHandler struct{}
+
+func (syncDiffHandler) OnStart(JobContext)                 {}
+func (syncDiffHandler) OnScanProgress(SyncScanProgress)    {}
+func (syncDiffHandler) OnTransferProgress(SyncJobProgress) {}
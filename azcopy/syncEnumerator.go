@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
@@ -168,6 +169,7 @@ func (s *syncer) initEnumerator(ctx context.Context, logLevel common.LogLevel, m
 	copyJobTemplate := &common.CopyJobPartOrderRequest{
 		JobID:               s.spt.jobID,
 		CommandString:       s.opts.commandString,
+		InvocationTime:      time.Now(),
 		FromTo:              s.opts.fromTo,
 		Fpo:                 fpo,
 		SymlinkHandlingType: s.opts.symlinks,
@@ -182,6 +184,7 @@ func (s *syncer) initEnumerator(ctx context.Context, logLevel common.LogLevel, m
 			BlockSizeInBytes:                 s.opts.blockSize,
 			PutBlobSizeInBytes:               s.opts.putBlobSize,
 			DeleteDestinationFileIfNecessary: s.opts.deleteDestinationFileIfNecessary,
+			PreserveContentDisposition:       true,
 		},
 		ForceWrite:                     common.EOverwriteOption.True(), // once we decide to transfer for a sync operation, we overwrite the destination regardless
 		ForceIfReadOnly:                s.opts.forceIfReadOnly,
@@ -195,6 +198,7 @@ func (s *syncer) initEnumerator(ctx context.Context, logLevel common.LogLevel, m
 		S2SInvalidMetadataHandleOption: common.EInvalidMetadataHandleOption.RenameIfInvalid(),
 		CpkOptions:                     s.opts.cpkOptions,
 		S2SPreserveBlobTags:            s.opts.s2SPreserveBlobTags,
+		ChangeDetectionMode:            s.opts.changeDetectionMode,
 
 		S2SSourceCredentialType: s.srp.srcCredType,
 		FileAttributes: common.FileTransferAttributes{
@@ -52,6 +52,7 @@ type cookedSyncOptions struct {
 	trailingDot             common.TrailingDotOption
 	includeRoot             bool
 	compareHash             common.SyncHashType
+	changeDetectionMode     common.ChangeDetectionMode
 	preservePermissions     common.PreservePermissionsOption
 	symlinks                common.SymlinkHandlingType
 	hardlinks               common.HardlinkHandlingType
@@ -191,6 +192,7 @@ func (s *cookedSyncOptions) applyDefaultsAndInferOptions(opts SyncOptions) (err
 	case common.ESyncHashType.MD5():
 		// Save any new MD5s on files we download.
 		s.putMd5 = true
+		s.changeDetectionMode = common.EChangeDetectionMode.MD5()
 	default: // no need to put a hash of any kind.
 	}
 
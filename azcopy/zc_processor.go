@@ -77,8 +77,22 @@ func (s *CopyTransferProcessor) ScheduleSyncRemoveSetPropertiesTransfer(storedOb
 	if storedObject.RelativePath == "\x00" { // Short circuit when we're talking about root/, because the STE is funky about this.
 		srcRelativePath, dstRelativePath = storedObject.RelativePath, storedObject.RelativePath
 	} else {
+		dstName := storedObject.RelativePath
+		if s.CopyJobTemplate.DestNameTemplate != "" && storedObject.EntityType == common.EEntityType.File() {
+			vars := common.DestNameVars(storedObject.ContainerName, storedObject.RelativePath, storedObject.BlobVersionID)
+			if dstName, err = common.RenderDestName(s.CopyJobTemplate.DestNameTemplate, vars); err != nil {
+				return err
+			}
+		}
+		if s.CopyJobTemplate.FlattenDestination {
+			if storedObject.EntityType == common.EEntityType.Folder() {
+				// flattened destinations have no folders
+				return nil
+			}
+			dstName = common.FlattenKey(dstName, common.Iff(s.CopyJobTemplate.FlattenSeparator != "", s.CopyJobTemplate.FlattenSeparator, common.DefaultFlattenSeparator))
+		}
 		srcRelativePath = PathEncodeRules(storedObject.RelativePath, s.CopyJobTemplate.FromTo, false, true)
-		dstRelativePath = PathEncodeRules(storedObject.RelativePath, s.CopyJobTemplate.FromTo, false, false)
+		dstRelativePath = PathEncodeRules(dstName, s.CopyJobTemplate.FromTo, false, false)
 		if srcRelativePath != "" {
 			srcRelativePath = "/" + srcRelativePath
 		}
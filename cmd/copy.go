@@ -594,6 +594,11 @@ type CookedCopyCmdArgs struct {
 	cpkByName                     string
 	cpkByValue                    bool
 	preserveOwner                 bool
+
+	// empty files left out while enumerating, for SkipZeroByteObjects
+	atomicSkippedZeroByteObjectCount uint32
+	// set when enumeration stopped at MaxTransfers
+	atomicTruncatedByLimit uint32
 }
 
 func (cca *CookedCopyCmdArgs) isRedirection() bool {
@@ -924,8 +929,11 @@ func (cca *CookedCopyCmdArgs) processCopyJobPartOrders() (err error) {
 			// Setting tags when tags explicitly provided by the user through blob-tags flag
 			BlobTagsString:                   cca.blobTagsMap.ToString(),
 			DeleteDestinationFileIfNecessary: cca.deleteDestinationFileIfNecessary,
+			// the enumerator already leaves out the source's disposition when properties aren't preserved
+			PreserveContentDisposition: true,
 		},
 		CommandString:  cca.commandString,
+		InvocationTime: time.Now(),
 		CredentialInfo: cca.credentialInfo,
 		FileAttributes: common.FileTransferAttributes{
 			TrailingDot: cca.trailingDot,
@@ -982,7 +990,7 @@ func (cca *CookedCopyCmdArgs) processCopyJobPartOrders() (err error) {
 	if cca.FromTo.IsS2S() && srcCredInfo.CredentialType.IsAzureOAuth() {
 		srcCred = common.NewScopedCredential(srcCredInfo.OAuthTokenInfo.TokenCredential, srcCredInfo.CredentialType)
 	}
-	options = traverser.CreateClientOptions(common.AzcopyCurrentJobLogger, srcCred, dstReauthTok)
+	options = traverser.CreateDestinationClientOptions(common.AzcopyCurrentJobLogger, srcCred, dstReauthTok)
 	jobPartOrder.DstServiceClient, err = common.GetServiceClientForLocation(
 		cca.FromTo.To(),
 		cca.Destination,
@@ -1189,6 +1197,7 @@ func (cca *CookedCopyCmdArgs) ReportProgressOrExit(lcm LifecycleMgr) (totalKnown
 		return common.Iff(timeElapsed != 0, bytesInMb/timeElapsed, 0) * 8
 	}
 	throughput := computeThroughput()
+	summary.ThroughputBytesPerSecond = throughput / 8 * azcopy.Base10Mega
 	builder := func(format OutputFormat) string {
 		if format == EOutputFormat.Json() {
 			jsonOutput, err := json.Marshal(summary)
@@ -1221,7 +1230,7 @@ func (cca *CookedCopyCmdArgs) ReportProgressOrExit(lcm LifecycleMgr) (totalKnown
 				summary.TransfersCompleted,
 				summary.TransfersFailed,
 				summary.TotalTransfers-(summary.TransfersCompleted+summary.TransfersFailed+summary.TransfersSkipped),
-				summary.TransfersSkipped+atomic.LoadUint32(&cca.atomicSkippedSymlinkCount)+atomic.LoadUint32(&cca.atomicSkippedSpecialFileCount),
+				summary.TransfersSkipped+atomic.LoadUint32(&cca.atomicSkippedSymlinkCount)+atomic.LoadUint32(&cca.atomicSkippedSpecialFileCount)+atomic.LoadUint32(&cca.atomicSkippedZeroByteObjectCount),
 				summary.TotalTransfers, scanningString, perfString, throughputString, diskString)
 		}
 	}
@@ -1239,6 +1248,8 @@ func (cca *CookedCopyCmdArgs) ReportProgressOrExit(lcm LifecycleMgr) (totalKnown
 		summary.SkippedSymlinkCount = atomic.LoadUint32(&cca.atomicSkippedSymlinkCount)
 		summary.SkippedSpecialFileCount = atomic.LoadUint32(&cca.atomicSkippedSpecialFileCount)
 		summary.SkippedHardlinkCount = atomic.LoadUint32(&cca.atomicSkippedHardlinkCount)
+		summary.SkippedZeroByteObjectCount = atomic.LoadUint32(&cca.atomicSkippedZeroByteObjectCount)
+		summary.TruncatedByLimit = atomic.LoadUint32(&cca.atomicTruncatedByLimit) == 1
 
 		exitCode := cca.getSuccessExitCode()
 		if summary.TransfersFailed > 0 || summary.JobStatus == common.EJobStatus.Cancelled() || summary.JobStatus == common.EJobStatus.Cancelling() {
@@ -1272,6 +1283,7 @@ Number of Symbolic Links Skipped: %v
 Number of Hardlinks Converted: %v
 Number of Hardlinks Skipped: %v
 Number of Special Files Skipped: %v
+Number of Zero-Byte Objects Skipped: %v
 Total Number of Bytes Transferred: %v
 Final Job Status: %v%s%s
 `,
@@ -1291,6 +1303,7 @@ Final Job Status: %v%s%s
 					summary.HardlinksConvertedCount,
 					summary.SkippedHardlinkCount,
 					summary.SkippedSpecialFileCount,
+					summary.SkippedZeroByteObjectCount,
 					summary.TotalBytesTransferred,
 					summary.JobStatus,
 					screenStats,
@@ -75,6 +75,16 @@ func (cca *CookedCopyCmdArgs) initEnumerator(jobPartOrder common.CopyJobPartOrde
 	jobPartOrder.S2SInvalidMetadataHandleOption = cca.s2sInvalidMetadataHandleOption
 	jobPartOrder.S2SPreserveBlobTags = cca.S2sPreserveBlobTags
 
+	if limit := int(jobPartOrder.MaxEnumerationConcurrency); limit != 0 && limit < traverser.EnumerationParallelism {
+		traverser.EnumerationParallelism = limit
+	}
+
+	// reading the objects' tags costs a request each, so it's only done when they're filtered by
+	var s3TagFilter func(tags map[string]string) bool
+	if jobPartOrder.FiltersByTag() {
+		s3TagFilter = jobPartOrder.PassesTagFilters
+	}
+
 	dest := cca.FromTo.To()
 	t, err = traverser.InitResourceTraverser(cca.Source, cca.FromTo.From(), ctx, traverser.InitResourceTraverserOptions{
 		DestResourceType: &dest,
@@ -89,6 +99,7 @@ func (cca *CookedCopyCmdArgs) initEnumerator(jobPartOrder common.CopyJobPartOrde
 
 		PreservePermissions: cca.preservePermissions,
 		SymlinkHandling:     cca.SymlinkHandling,
+		SymlinkLoopPolicy:   jobPartOrder.SymlinkLoopPolicy,
 		PermanentDelete:     cca.permanentDeleteOption,
 		SyncHashType:        common.ESyncHashType.None(),
 		TrailingDotOption:   cca.trailingDot,
@@ -96,6 +107,12 @@ func (cca *CookedCopyCmdArgs) initEnumerator(jobPartOrder common.CopyJobPartOrde
 		Recursive:               cca.Recursive,
 		GetPropertiesInFrontend: getRemoteProperties,
 		IncludeDirectoryStubs:   cca.IncludeDirectoryStubs,
+		IncludeDirMarkers:       jobPartOrder.PreserveEmptyDirectories,
+		RequesterPays:           jobPartOrder.RequesterPays,
+		MapStorageClassToTier:   jobPartOrder.BlobAttributes.MapStorageClassToTier,
+		InventoryManifestURL:    jobPartOrder.InventoryManifestURL,
+		S3Region:                jobPartOrder.SourceRegion,
+		S3TagFilter:             s3TagFilter,
 		PreserveBlobTags:        cca.S2sPreserveBlobTags,
 		StripTopDir:             cca.StripTopDir,
 		HardlinkHandling:        cca.hardlinks,
@@ -289,12 +306,22 @@ func (cca *CookedCopyCmdArgs) initEnumerator(jobPartOrder common.CopyJobPartOrde
 	// decide our folder transfer strategy
 	var message string
 	jobPartOrder.Fpo, message = azcopy.NewFolderPropertyOption(cca.FromTo, cca.Recursive, cca.StripTopDir, filters, cca.preserveInfo,
-		cca.preservePermissions.IsTruthy(), cca.preservePOSIXProperties, strings.EqualFold(cca.Destination.Value, common.Dev_Null), cca.IncludeDirectoryStubs)
+		cca.preservePermissions.IsTruthy(), cca.preservePOSIXProperties, strings.EqualFold(cca.Destination.Value, common.Dev_Null), cca.IncludeDirectoryStubs || jobPartOrder.PreserveEmptyDirectories)
+	if depth := jobPartOrder.MaxDepth; depth != nil && *depth >= 0 {
+		filters = append(filters, &traverser.MaxDepthFilter{MaxDepth: int(*depth)})
+	}
+	if jobPartOrder.SkipZeroByteObjects {
+		filters = append(filters, &traverser.SkipObjectFilter{
+			Skips:  jobPartOrder.SkipsObject,
+			OnSkip: func() { atomic.AddUint32(&cca.atomicSkippedZeroByteObjectCount, 1) },
+		})
+	}
 	if !cca.dryrunMode {
 		glcm.Info(message)
 	}
 	common.LogToJobLogWithPrefix(message, common.LogInfo)
 
+	var scheduledTransfers uint64
 	processor := func(object traverser.StoredObject) error {
 		// Start by resolving the name and creating the container
 		if object.ContainerName != "" {
@@ -330,6 +357,13 @@ func (cca *CookedCopyCmdArgs) initEnumerator(jobPartOrder common.CopyJobPartOrde
 		dstRelPath := cca.MakeEscapedRelativePath(false, isDestDir, object)
 
 		transfer, shouldSendToSte := object.ToNewCopyTransfer(cca.autoDecompress && cca.FromTo.IsDownload(), srcRelPath, dstRelPath, cca.s2sPreserveAccessTier.Value(), jobPartOrder.Fpo, cca.SymlinkHandling, cca.hardlinks)
+		if shouldSendToSte {
+			if !jobPartOrder.AllowsMoreTransfers(scheduledTransfers) {
+				atomic.StoreUint32(&cca.atomicTruncatedByLimit, 1)
+				return traverser.ErrTransferLimitReached
+			}
+			scheduledTransfers++
+		}
 		if !cca.S2sPreserveBlobTags {
 			transfer.BlobTags = cca.blobTagsMap
 		}
@@ -615,6 +649,9 @@ func (cca *CookedCopyCmdArgs) MakeEscapedRelativePath(source bool, dstIsDir bool
 	// If it's out here, the object is contained in a folder, or was found via a wildcard, or object.isSourceRootFolder == true
 	if object.IsSourceRootFolder() {
 		relativePath = "" // otherwise we get "/" from the line below, and that breaks some clients, e.g. blobFS
+	} else if cca.FromTo.From() == common.ELocation.Local() && common.OS_PATH_SEPARATOR == `\` {
+		// Windows paths can mix and repeat separators; elsewhere a back slash is a legal part of a file name
+		relativePath = "/" + common.NormalizeKeySeparators(object.RelativePath)
 	} else {
 		relativePath = "/" + strings.Replace(object.RelativePath, common.OS_PATH_SEPARATOR, common.AZCOPY_PATH_SEPARATOR_STRING, -1)
 	}
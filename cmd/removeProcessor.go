@@ -21,6 +21,8 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/Azure/azure-storage-azcopy/v10/azcopy"
 	"github.com/Azure/azure-storage-azcopy/v10/common"
 )
@@ -30,6 +32,7 @@ func newRemoveTransferProcessor(cca *CookedCopyCmdArgs, numOfTransfersPerPart in
 	copyJobTemplate := &common.CopyJobPartOrderRequest{
 		JobID:                 cca.jobID,
 		CommandString:         cca.commandString,
+		InvocationTime:        time.Now(),
 		FromTo:                cca.FromTo,
 		Fpo:                   fpo,
 		SymlinkHandlingType:   common.ESymlinkHandlingType.Preserve(),       // We want to delete symlinks
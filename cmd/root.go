@@ -75,6 +75,8 @@ var rootCmd = &cobra.Command{
 	Long:    rootCmdLongDescription,
 	// PersistentPreRunE hook will not run on just `azcopy` without any subcommand
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// don't exit before the finished jobs' completion webhooks are notified
+		glcm.RegisterCloseFunc(jobsAdmin.WaitForCompletionWebhooks)
 		glcm.RegisterCloseFunc(func() {
 			if debugMemoryProfile != "" {
 				memProfDir := filepath.Dir(debugMemoryProfile)
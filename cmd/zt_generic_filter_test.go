@@ -37,6 +37,25 @@ type genericFilterSuite struct{}
 
 var _ = chk.Suite(&genericFilterSuite{})
 
+func TestMaxDepthFilter(t *testing.T) {
+	a := assert.New(t)
+	passes := func(maxDepth int, relativePath string) bool {
+		filter := &traverser.MaxDepthFilter{MaxDepth: maxDepth}
+		return filter.DoesPass(traverser.StoredObject{RelativePath: relativePath})
+	}
+
+	// 0 keeps the root's immediate children, folders included
+	a.True(passes(0, "top.txt"))
+	a.True(passes(0, "dir"))
+	a.False(passes(0, "dir/nested.txt"))
+	// a single file source has no relative path
+	a.True(passes(0, ""))
+
+	a.True(passes(1, "dir/nested.txt"))
+	a.True(passes(1, "dir/sub"))
+	a.False(passes(1, "dir/sub/deeper.txt"))
+}
+
 func TestIncludeFilter(t *testing.T) {
 	a := assert.New(t)
 	// set up the filters
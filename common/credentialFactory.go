@@ -24,6 +24,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"sync"
 
 	gcpUtils "cloud.google.com/go/storage"
@@ -58,16 +59,29 @@ func CreateS3Credential(ctx context.Context, credInfo CredentialInfo) (*credenti
 // S3 credential related factory methods
 // ==============================================================================================
 func CreateS3Client(ctx context.Context, credInfo CredentialInfo, logger ILogger) (*minio.Client, error) {
+	bucketLookup := minio.BucketLookupAuto
+	if credInfo.S3CredentialInfo.PathStyle {
+		bucketLookup = minio.BucketLookupPath
+	}
 	if credInfo.CredentialType == ECredentialType.S3PublicBucket() {
 		cred := credentials.NewStatic("", "", "", credentials.SignatureAnonymous)
-		return minio.NewWithOptions(credInfo.S3CredentialInfo.Endpoint, &minio.Options{Creds: cred, Secure: true, Region: credInfo.S3CredentialInfo.Region})
+		s3Client, err := minio.NewWithOptions(credInfo.S3CredentialInfo.Endpoint, &minio.Options{Creds: cred, Secure: true, Region: credInfo.S3CredentialInfo.Region, BucketLookup: bucketLookup})
+		if err != nil {
+			return nil, err
+		}
+		setS3HTTPVersion(s3Client, credInfo.S3CredentialInfo.HTTPVersion)
+		return s3Client, nil
 	}
 	// Support access key
 	credential, err := CreateS3Credential(ctx, credInfo)
 	if err != nil {
 		return nil, err
 	}
-	s3Client, err := minio.NewWithCredentials(credInfo.S3CredentialInfo.Endpoint, credential, true, credInfo.S3CredentialInfo.Region)
+	s3Client, err := minio.NewWithOptions(credInfo.S3CredentialInfo.Endpoint, &minio.Options{Creds: credential, Secure: true, Region: credInfo.S3CredentialInfo.Region, BucketLookup: bucketLookup})
+	if err != nil {
+		return nil, err
+	}
+	setS3HTTPVersion(s3Client, credInfo.S3CredentialInfo.HTTPVersion)
 
 	if logger != nil {
 		s3Client.TraceOn(NewS3HTTPTraceLogger(logger, LogDebug))
@@ -75,6 +89,17 @@ func CreateS3Client(ctx context.Context, credInfo CredentialInfo, logger ILogger
 	return s3Client, err
 }
 
+// setS3HTTPVersion gives the client a copy of minio's default transport set up for the preferred HTTP version.
+// The default transport is shared by every client, so it's left alone when there's no preference.
+func setS3HTTPVersion(s3Client *minio.Client, preference HTTPVersionPreference) {
+	if preference == EHTTPVersionPreference.Auto() {
+		return
+	}
+	transport := minio.DefaultTransport.(*http.Transport).Clone()
+	preference.ConfigureTransport(transport)
+	s3Client.SetCustomTransport(transport)
+}
+
 type S3ClientFactory struct {
 	s3Clients map[S3CredentialInfo]*minio.Client
 	lock      sync.RWMutex
@@ -121,6 +121,16 @@ func (bs ByteSliceExtension) RemoveBOM() []byte {
 
 /////////////////////////////////////////////////////////////////////////////////////////////////
 
+// NormalizeKeySeparators turns a local relative path into an object key's form, where back slashes become forward ones
+// and runs of slashes are collapsed into one. Windows paths can mix both separators, and repeat them, but keys can't.
+func NormalizeKeySeparators(key string) string {
+	key = strings.ReplaceAll(key, `\`, AZCOPY_PATH_SEPARATOR_STRING)
+	for strings.Contains(key, "//") {
+		key = strings.ReplaceAll(key, "//", AZCOPY_PATH_SEPARATOR_STRING)
+	}
+	return key
+}
+
 func DeterminePathSeparator(path string) string {
 	// Just use forward-slash everywhere that isn't windows.
 	if runtime.GOOS == "windows" && strings.Contains(path, `\`) {
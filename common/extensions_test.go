@@ -39,6 +39,23 @@ func TestGenerateFullPath(t *testing.T) {
 	}
 }
 
+func TestNormalizeKeySeparators(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := map[string]string{
+		`dir\sub\file.txt`:    "dir/sub/file.txt", // Windows-style
+		`dir\\sub\\file.txt`:  "dir/sub/file.txt", // repeated
+		`dir/sub\file.txt`:    "dir/sub/file.txt", // mixed
+		`dir\/sub//\file.txt`: "dir/sub/file.txt", // mixed and repeated
+		`\dir\`:               "/dir/",            // leading and trailing separators are kept
+		"dir/sub/file.txt":    "dir/sub/file.txt", // already normalized
+		"":                    "",
+	}
+	for input, expected := range testCases {
+		a.Equal(expected, NormalizeKeySeparators(input), input)
+	}
+}
+
 func TestURLWithPlusDecodedInPath(t *testing.T) {
 	a := assert.New(t)
 	type expectedResults struct {
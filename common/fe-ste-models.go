@@ -21,15 +21,20 @@
 package common
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"net/http"
 	"os"
 	"reflect"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -653,6 +658,12 @@ func (l Location) SupportsTrailingDot() bool {
 	return false
 }
 
+// ProvidesMD5Cheaply reports whether the location's files come with their MD5 hashes, so comparing them doesn't
+// mean reading the files. Local files have to be hashed, and S3's ETags aren't MD5s for multipart uploads.
+func (l Location) ProvidesMD5Cheaply() bool {
+	return l.IsRemote() && l != ELocation.S3()
+}
+
 func (ft FromTo) IsRedirection() bool {
 	return ft == EFromTo.PipeBlob() || ft == EFromTo.BlobPipe()
 }
@@ -897,6 +908,9 @@ func (TransferStatus) TierAvailabilityCheckFailure() TransferStatus { return Tra
 
 func (TransferStatus) Cancelled() TransferStatus { return TransferStatus(-6) }
 
+// Transfer skipped because AzCopy isn't permitted to read the local source file.
+func (TransferStatus) SkippedReadDenied() TransferStatus { return TransferStatus(-7) }
+
 // Transfer is any of the three possible state (InProgress, Completer or Failed)
 func (TransferStatus) All() TransferStatus { return TransferStatus(math.MaxInt8) }
 func (ts TransferStatus) String() string {
@@ -1148,6 +1162,214 @@ func (i *InvalidMetadataHandleOption) UnmarshalJSON(b []byte) error {
 	return i.Parse(s)
 }
 
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var EMetadataKeyCasePolicy = MetadataKeyCasePolicy(0)
+
+// MetadataKeyCasePolicy is how the case of metadata keys is carried over to destinations whose keys are case-insensitive,
+// like Azure's, from sources whose keys aren't, like S3's.
+type MetadataKeyCasePolicy uint8
+
+// Preserve keeps the keys as they are; keys that differ only by case then overwrite each other at the destination.
+func (MetadataKeyCasePolicy) Preserve() MetadataKeyCasePolicy { return MetadataKeyCasePolicy(0) }
+
+// Lowercase lowercases the keys. Of the keys that differ only by case, the value of the first in sorted order is kept.
+func (MetadataKeyCasePolicy) Lowercase() MetadataKeyCasePolicy { return MetadataKeyCasePolicy(1) }
+
+// FailOnCollision keeps the keys as they are, but fails the transfers whose keys differ only by case.
+func (MetadataKeyCasePolicy) FailOnCollision() MetadataKeyCasePolicy { return MetadataKeyCasePolicy(2) }
+
+func (p MetadataKeyCasePolicy) String() string {
+	return enum.StringInt(p, reflect.TypeOf(p))
+}
+
+func (p *MetadataKeyCasePolicy) Parse(s string) error {
+	val, err := enum.ParseInt(reflect.TypeOf(p), s, true, true)
+	if err == nil {
+		*p = val.(MetadataKeyCasePolicy)
+	}
+	return err
+}
+
+func (p MetadataKeyCasePolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p *MetadataKeyCasePolicy) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return p.Parse(s)
+}
+
+var EOverwriteCheckMode = OverwriteCheckMode(0)
+
+// OverwriteCheckMode is when the engine checks whether a destination exists before transferring to it, trading the
+// correctness of the overwrite decisions for fewer requests. See ChecksDestination.
+type OverwriteCheckMode uint8
+
+// OnlyIfPolicyNeeds checks only when the overwrite option depends on the destination, i.e. when it isn't True.
+func (OverwriteCheckMode) OnlyIfPolicyNeeds() OverwriteCheckMode { return OverwriteCheckMode(0) }
+
+// Always checks, even when the destination is overwritten regardless.
+func (OverwriteCheckMode) Always() OverwriteCheckMode { return OverwriteCheckMode(1) }
+
+// Never checks, so destinations are overwritten whatever the overwrite option.
+func (OverwriteCheckMode) Never() OverwriteCheckMode { return OverwriteCheckMode(2) }
+
+func (m OverwriteCheckMode) String() string {
+	return enum.StringInt(m, reflect.TypeOf(m))
+}
+
+func (m *OverwriteCheckMode) Parse(s string) error {
+	val, err := enum.ParseInt(reflect.TypeOf(m), s, true, true)
+	if err == nil {
+		*m = val.(OverwriteCheckMode)
+	}
+	return err
+}
+
+func (m OverwriteCheckMode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+func (m *OverwriteCheckMode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return m.Parse(s)
+}
+
+// ChecksDestination reports whether the destination's existence is checked before transferring to it with the given overwrite option.
+func (m OverwriteCheckMode) ChecksDestination(overwrite OverwriteOption) bool {
+	switch m {
+	case EOverwriteCheckMode.Always():
+		return true
+	case EOverwriteCheckMode.Never():
+		return false
+	default:
+		return overwrite != EOverwriteOption.True()
+	}
+}
+
+var EChangeDetectionMode = ChangeDetectionMode(0)
+
+// ChangeDetectionMode is how sync decides that a source file changed, and so must be transferred again. See HasChanged.
+type ChangeDetectionMode uint8
+
+// ModTime counts a file as changed when the source was modified after the destination.
+func (ChangeDetectionMode) ModTime() ChangeDetectionMode { return ChangeDetectionMode(0) }
+
+// Size counts a file as changed when the sizes differ, e.g. for locations whose times can't be trusted.
+func (ChangeDetectionMode) Size() ChangeDetectionMode { return ChangeDetectionMode(1) }
+
+// SizeAndModTime counts a file as changed when either ModTime or Size would.
+func (ChangeDetectionMode) SizeAndModTime() ChangeDetectionMode { return ChangeDetectionMode(2) }
+
+// MD5 counts a file as changed when the contents' hashes differ. Files lacking a hash fall back to ModTime.
+func (ChangeDetectionMode) MD5() ChangeDetectionMode { return ChangeDetectionMode(3) }
+
+func (m ChangeDetectionMode) String() string {
+	return enum.StringInt(m, reflect.TypeOf(m))
+}
+
+func (m *ChangeDetectionMode) Parse(s string) error {
+	val, err := enum.ParseInt(reflect.TypeOf(m), s, true, true)
+	if err == nil {
+		*m = val.(ChangeDetectionMode)
+	}
+	return err
+}
+
+func (m ChangeDetectionMode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+func (m *ChangeDetectionMode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return m.Parse(s)
+}
+
+// ChangeDetectionProperties are the properties of a file that ChangeDetectionMode.HasChanged compares.
+// MD5 is nil when the file has no hash.
+type ChangeDetectionProperties struct {
+	Size         int64
+	LastModified time.Time
+	MD5          []byte
+}
+
+// HasChanged reports whether src must be transferred again over dst.
+func (m ChangeDetectionMode) HasChanged(src, dst ChangeDetectionProperties) bool {
+	switch m {
+	case EChangeDetectionMode.Size():
+		return src.Size != dst.Size
+	case EChangeDetectionMode.SizeAndModTime():
+		return src.Size != dst.Size || src.LastModified.After(dst.LastModified)
+	case EChangeDetectionMode.MD5():
+		if src.MD5 == nil || dst.MD5 == nil {
+			return src.LastModified.After(dst.LastModified)
+		}
+		return !bytes.Equal(src.MD5, dst.MD5)
+	default:
+		return src.LastModified.After(dst.LastModified)
+	}
+}
+
+// DetectMetadataCollisions returns the keys of m that collide with another once case is ignored, sorted,
+// e.g. "Owner" and "owner". It returns nil when there are none.
+func DetectMetadataCollisions(m map[string]string) []string {
+	return collidingKeys(m)
+}
+
+func collidingKeys[V any](m map[string]V) []string {
+	byFoldedKey := make(map[string][]string, len(m))
+	for k := range m {
+		folded := strings.ToLower(k)
+		byFoldedKey[folded] = append(byFoldedKey[folded], k)
+	}
+	var collisions []string
+	for _, keys := range byFoldedKey {
+		if len(keys) > 1 {
+			collisions = append(collisions, keys...)
+		}
+	}
+	sort.Strings(collisions)
+	return collisions
+}
+
+// ApplyKeyCasePolicy returns the metadata with its keys' case handled as policy asks; see MetadataKeyCasePolicy.
+// It fails for FailOnCollision when keys collide, naming them.
+func (m Metadata) ApplyKeyCasePolicy(policy MetadataKeyCasePolicy) (Metadata, error) {
+	switch policy {
+	case EMetadataKeyCasePolicy.FailOnCollision():
+		if collisions := collidingKeys(m); len(collisions) > 0 {
+			return nil, fmt.Errorf("the metadata keys %s collide once case is ignored", strings.Join(collisions, ", "))
+		}
+	case EMetadataKeyCasePolicy.Lowercase():
+		if m == nil {
+			return m, nil
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		lowercased := make(Metadata, len(m))
+		for _, k := range keys {
+			if _, exists := lowercased[strings.ToLower(k)]; !exists {
+				lowercased[strings.ToLower(k)] = m[k]
+			}
+		}
+		return lowercased, nil
+	}
+	return m, nil
+}
+
 // //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 const (
 	DefaultBlockBlobBlockSize      = 8 * 1024 * 1024
@@ -1191,8 +1413,21 @@ type CopyTransfer struct {
 	BlobTags BlobTags
 
 	BlobSnapshotID string
+
+	// Range, when set, limits the transfer to a range of the source's bytes, e.g. to resume a large object's copy.
+	// Nil transfers the whole source. It's checked by CopyJobPartOrderRequest.Validate, but not yet persisted in the plan files.
+	Range *TransferRange
 }
 
+// TransferRange is the range of bytes from Start to End, both inclusive. An End of TransferRangeToEnd runs to the end of the source.
+type TransferRange struct {
+	Start int64
+	End   int64
+}
+
+// TransferRangeToEnd is the TransferRange End that runs to the end of the source.
+const TransferRangeToEnd int64 = -1
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // Metadata used in AzCopy.
@@ -1655,6 +1890,93 @@ func GetCompressionType(contentEncoding string) (CompressionType, error) {
 
 /////////////////////////////////////////////////////////////////
 
+// CompressionAlgorithm is how uploads are compressed, when BlobTransferAttributes.CompressOnUpload is set
+var ECompressionAlgorithm = CompressionAlgorithm(0)
+
+type CompressionAlgorithm uint8
+
+func (CompressionAlgorithm) None() CompressionAlgorithm { return CompressionAlgorithm(0) }
+func (CompressionAlgorithm) Gzip() CompressionAlgorithm { return CompressionAlgorithm(1) }
+
+func (ca CompressionAlgorithm) String() string {
+	return enum.StringInt(ca, reflect.TypeOf(ca))
+}
+
+func (ca *CompressionAlgorithm) Parse(s string) error {
+	val, err := enum.ParseInt(reflect.TypeOf(ca), s, true, true)
+	if err == nil {
+		*ca = val.(CompressionAlgorithm)
+	}
+	return err
+}
+
+// ContentEncoding returns the Content-Encoding of data compressed with the algorithm
+func (ca CompressionAlgorithm) ContentEncoding() string {
+	switch ca {
+	case ECompressionAlgorithm.Gzip():
+		return "gzip"
+	default:
+		return ""
+	}
+}
+
+/////////////////////////////////////////////////////////////////
+
+// LineEndingMode is how the line endings of text files are translated as they're uploaded, for the files whose
+// extensions are listed in BlobTransferAttributes.TextExtensions
+var ELineEndingMode = LineEndingMode(0)
+
+type LineEndingMode uint8
+
+func (LineEndingMode) None() LineEndingMode   { return LineEndingMode(0) }
+func (LineEndingMode) ToLF() LineEndingMode   { return LineEndingMode(1) }
+func (LineEndingMode) ToCRLF() LineEndingMode { return LineEndingMode(2) }
+
+func (m LineEndingMode) String() string {
+	return enum.StringInt(m, reflect.TypeOf(m))
+}
+
+func (m *LineEndingMode) Parse(s string) error {
+	val, err := enum.ParseInt(reflect.TypeOf(m), s, true, true)
+	if err == nil {
+		*m = val.(LineEndingMode)
+	}
+	return err
+}
+
+// Translate copies src to dst with its line endings translated. ToLF turns CRLFs into LFs, and ToCRLF turns the LFs
+// that aren't already preceded by a CR into CRLFs; lone CRs are left alone either way.
+func (m LineEndingMode) Translate(dst io.Writer, src io.Reader) error {
+	if m == ELineEndingMode.None() {
+		_, err := io.Copy(dst, src)
+		return err
+	}
+
+	r, w := bufio.NewReader(src), bufio.NewWriter(dst)
+	var previous byte
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		switch {
+		case m == ELineEndingMode.ToLF() && b == '\r':
+			if next, _ := r.Peek(1); len(next) == 1 && next[0] == '\n' {
+				continue
+			}
+		case m == ELineEndingMode.ToCRLF() && b == '\n' && previous != '\r':
+			_ = w.WriteByte('\r')
+		}
+		_ = w.WriteByte(b) // the writer's errors stick, so they're returned by Flush
+		previous = b
+	}
+	return w.Flush()
+}
+
+/////////////////////////////////////////////////////////////////
+
 var EEntityType = EntityType(0)
 
 type EntityType uint8
@@ -1892,6 +2214,68 @@ func (sht *SymlinkHandlingType) Determine(Follow, Preserve bool) error {
 
 // /////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// SymlinkLoopPolicy is what enumeration does when following a symlink leads back to a directory it has already
+// enumerated, as in a loop. Skip leaves the link out with a warning, Ignore leaves it out silently, and Error fails.
+var ESymlinkLoopPolicy = SymlinkLoopPolicy(0)
+
+type SymlinkLoopPolicy uint8
+
+func (SymlinkLoopPolicy) Skip() SymlinkLoopPolicy   { return SymlinkLoopPolicy(0) }
+func (SymlinkLoopPolicy) Error() SymlinkLoopPolicy  { return SymlinkLoopPolicy(1) }
+func (SymlinkLoopPolicy) Ignore() SymlinkLoopPolicy { return SymlinkLoopPolicy(2) }
+
+func (p SymlinkLoopPolicy) String() string {
+	return enum.StringInt(p, reflect.TypeOf(p))
+}
+
+func (p *SymlinkLoopPolicy) Parse(s string) error {
+	val, err := enum.ParseInt(reflect.TypeOf(p), s, true, true)
+	if err == nil {
+		*p = val.(SymlinkLoopPolicy)
+	}
+	return err
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// HTTPVersionPreference is the HTTP version the engine's connections use. Auto leaves it to the transport,
+// ForceHTTP1 never negotiates HTTP/2, e.g. for proxies that misbehave under it, and PreferHTTP2 offers HTTP/2
+// even when the transport wouldn't by default. See ConfigureTransport.
+var EHTTPVersionPreference = HTTPVersionPreference(0)
+
+type HTTPVersionPreference uint8
+
+func (HTTPVersionPreference) Auto() HTTPVersionPreference        { return HTTPVersionPreference(0) }
+func (HTTPVersionPreference) ForceHTTP1() HTTPVersionPreference  { return HTTPVersionPreference(1) }
+func (HTTPVersionPreference) PreferHTTP2() HTTPVersionPreference { return HTTPVersionPreference(2) }
+
+func (p HTTPVersionPreference) String() string {
+	return enum.StringInt(p, reflect.TypeOf(p))
+}
+
+func (p *HTTPVersionPreference) Parse(s string) error {
+	val, err := enum.ParseInt(reflect.TypeOf(p), s, true, true)
+	if err == nil {
+		*p = val.(HTTPVersionPreference)
+	}
+	return err
+}
+
+// ConfigureTransport sets up t to use the preferred HTTP version. HTTP/2 is only ever negotiated over TLS.
+func (p HTTPVersionPreference) ConfigureTransport(t *http.Transport) {
+	switch p {
+	case EHTTPVersionPreference.ForceHTTP1():
+		// a non-nil, empty map disables HTTP/2
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	case EHTTPVersionPreference.PreferHTTP2():
+		// otherwise it isn't attempted by transports with a custom dialer or TLS config
+		t.ForceAttemptHTTP2 = true
+	}
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 var oncer = sync.Once{}
 
 func WarnIfTooManyObjects() {
@@ -23,7 +23,10 @@ package common_test
 import (
 	"github.com/Azure/azure-storage-azcopy/v10/common"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestEnhanceJobStatusInfo(t *testing.T) {
@@ -185,3 +188,251 @@ func TestMetadataResolveInvalidKeyNegative(t *testing.T) {
 	_, err = mNegative3.ResolveInvalidKey()
 	a.NotNil(err)
 }
+
+func TestCompressionAlgorithm(t *testing.T) {
+	a := assert.New(t)
+
+	for _, ca := range []common.CompressionAlgorithm{common.ECompressionAlgorithm.None(), common.ECompressionAlgorithm.Gzip()} {
+		var parsed common.CompressionAlgorithm
+		a.NoError(parsed.Parse(ca.String()))
+		a.Equal(ca, parsed)
+	}
+
+	var parsed common.CompressionAlgorithm
+	a.NoError(parsed.Parse("gzip"))
+	a.Equal(common.ECompressionAlgorithm.Gzip(), parsed)
+	a.Error(parsed.Parse("brotli"))
+
+	a.Equal("gzip", common.ECompressionAlgorithm.Gzip().ContentEncoding())
+	a.Equal("", common.ECompressionAlgorithm.None().ContentEncoding())
+}
+
+func TestDetectMetadataCollisions(t *testing.T) {
+	a := assert.New(t)
+
+	a.Nil(common.DetectMetadataCollisions(nil))
+	a.Nil(common.DetectMetadataCollisions(map[string]string{"owner": "a", "team": "b"}))
+	a.Equal([]string{"OWNER", "Owner", "owner"},
+		common.DetectMetadataCollisions(map[string]string{"owner": "a", "Owner": "b", "OWNER": "c", "team": "d"}))
+	a.Equal([]string{"Owner", "Team", "owner", "team"},
+		common.DetectMetadataCollisions(map[string]string{"owner": "a", "Owner": "b", "team": "c", "Team": "d", "other": "e"}))
+}
+
+func TestMetadataApplyKeyCasePolicy(t *testing.T) {
+	a := assert.New(t)
+
+	colliding := toCommonMetadata(map[string]string{"Owner": "upper", "owner": "lower", "Team": "t"})
+
+	preserved, err := colliding.ApplyKeyCasePolicy(common.EMetadataKeyCasePolicy.Preserve())
+	a.NoError(err)
+	a.Equal(colliding, preserved)
+
+	// the first of the colliding keys in sorted order wins
+	lowercased, err := colliding.ApplyKeyCasePolicy(common.EMetadataKeyCasePolicy.Lowercase())
+	a.NoError(err)
+	a.Len(lowercased, 2)
+	a.Equal("upper", *lowercased["owner"])
+	a.Equal("t", *lowercased["team"])
+
+	_, err = colliding.ApplyKeyCasePolicy(common.EMetadataKeyCasePolicy.FailOnCollision())
+	a.ErrorContains(err, "Owner, owner")
+
+	distinct := toCommonMetadata(map[string]string{"Owner": "a", "Team": "b"})
+	kept, err := distinct.ApplyKeyCasePolicy(common.EMetadataKeyCasePolicy.FailOnCollision())
+	a.NoError(err)
+	a.Equal(distinct, kept)
+
+	var parsed common.MetadataKeyCasePolicy
+	a.NoError(parsed.Parse("FailOnCollision"))
+	a.Equal(common.EMetadataKeyCasePolicy.FailOnCollision(), parsed)
+}
+
+func TestOverwriteCheckMode(t *testing.T) {
+	a := assert.New(t)
+
+	for _, mode := range []common.OverwriteCheckMode{common.EOverwriteCheckMode.OnlyIfPolicyNeeds(), common.EOverwriteCheckMode.Always(), common.EOverwriteCheckMode.Never()} {
+		var parsed common.OverwriteCheckMode
+		a.NoError(parsed.Parse(mode.String()))
+		a.Equal(mode, parsed)
+
+		raw, err := mode.MarshalJSON()
+		a.NoError(err)
+		a.Equal(`"`+mode.String()+`"`, string(raw))
+		parsed = common.OverwriteCheckMode(0)
+		a.NoError(parsed.UnmarshalJSON(raw))
+		a.Equal(mode, parsed)
+	}
+
+	var parsed common.OverwriteCheckMode
+	a.Error(parsed.Parse("Sometimes"))
+	// orders that don't say check as they always have
+	a.Equal(common.EOverwriteCheckMode.OnlyIfPolicyNeeds(), parsed)
+}
+
+func TestOverwriteCheckModeChecksDestination(t *testing.T) {
+	a := assert.New(t)
+	options := []common.OverwriteOption{common.EOverwriteOption.True(), common.EOverwriteOption.False(), common.EOverwriteOption.Prompt(), common.EOverwriteOption.IfSourceNewer()}
+
+	for _, overwrite := range options {
+		a.True(common.EOverwriteCheckMode.Always().ChecksDestination(overwrite), overwrite.String())
+		a.False(common.EOverwriteCheckMode.Never().ChecksDestination(overwrite), overwrite.String())
+	}
+
+	// only overwriting regardless makes the check unneeded
+	a.False(common.EOverwriteCheckMode.OnlyIfPolicyNeeds().ChecksDestination(common.EOverwriteOption.True()))
+	for _, overwrite := range options[1:] {
+		a.True(common.EOverwriteCheckMode.OnlyIfPolicyNeeds().ChecksDestination(overwrite), overwrite.String())
+	}
+}
+
+func TestChangeDetectionMode(t *testing.T) {
+	a := assert.New(t)
+
+	for _, mode := range []common.ChangeDetectionMode{common.EChangeDetectionMode.ModTime(), common.EChangeDetectionMode.Size(), common.EChangeDetectionMode.SizeAndModTime(), common.EChangeDetectionMode.MD5()} {
+		var parsed common.ChangeDetectionMode
+		a.NoError(parsed.Parse(mode.String()))
+		a.Equal(mode, parsed)
+
+		raw, err := mode.MarshalJSON()
+		a.NoError(err)
+		a.Equal(`"`+mode.String()+`"`, string(raw))
+		parsed = common.ChangeDetectionMode(0)
+		a.NoError(parsed.UnmarshalJSON(raw))
+		a.Equal(mode, parsed)
+	}
+
+	var parsed common.ChangeDetectionMode
+	a.NoError(parsed.Parse("sizeandmodtime"))
+	a.Equal(common.EChangeDetectionMode.SizeAndModTime(), parsed)
+	a.Error(parsed.Parse("CRC64"))
+}
+
+func TestChangeDetectionModeHasChanged(t *testing.T) {
+	a := assert.New(t)
+	earlier := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	dst := common.ChangeDetectionProperties{Size: 10, LastModified: earlier, MD5: []byte{1, 2}}
+
+	newer := common.ChangeDetectionProperties{Size: 10, LastModified: later, MD5: []byte{1, 2}}
+	resized := common.ChangeDetectionProperties{Size: 20, LastModified: earlier, MD5: []byte{1, 2}}
+	rehashed := common.ChangeDetectionProperties{Size: 10, LastModified: earlier, MD5: []byte{3, 4}}
+	same := dst
+
+	mode := common.EChangeDetectionMode.ModTime()
+	a.True(mode.HasChanged(newer, dst))
+	a.False(mode.HasChanged(resized, dst))
+	a.False(mode.HasChanged(rehashed, dst))
+	a.False(mode.HasChanged(same, dst))
+	// an older source isn't a change
+	a.False(mode.HasChanged(dst, newer))
+
+	mode = common.EChangeDetectionMode.Size()
+	a.False(mode.HasChanged(newer, dst))
+	a.True(mode.HasChanged(resized, dst))
+	a.False(mode.HasChanged(rehashed, dst))
+	a.False(mode.HasChanged(same, dst))
+
+	mode = common.EChangeDetectionMode.SizeAndModTime()
+	a.True(mode.HasChanged(newer, dst))
+	a.True(mode.HasChanged(resized, dst))
+	a.False(mode.HasChanged(rehashed, dst))
+	a.False(mode.HasChanged(same, dst))
+
+	mode = common.EChangeDetectionMode.MD5()
+	a.False(mode.HasChanged(newer, dst))
+	a.False(mode.HasChanged(resized, dst))
+	a.True(mode.HasChanged(rehashed, dst))
+	a.False(mode.HasChanged(same, dst))
+	// without both hashes, the times decide
+	a.True(mode.HasChanged(common.ChangeDetectionProperties{Size: 10, LastModified: later}, dst))
+	a.False(mode.HasChanged(common.ChangeDetectionProperties{Size: 10, LastModified: earlier}, dst))
+}
+
+func TestLineEndingMode(t *testing.T) {
+	a := assert.New(t)
+
+	for _, mode := range []common.LineEndingMode{common.ELineEndingMode.None(), common.ELineEndingMode.ToLF(), common.ELineEndingMode.ToCRLF()} {
+		var parsed common.LineEndingMode
+		a.NoError(parsed.Parse(mode.String()))
+		a.Equal(mode, parsed)
+	}
+
+	var parsed common.LineEndingMode
+	a.NoError(parsed.Parse("tocrlf"))
+	a.Equal(common.ELineEndingMode.ToCRLF(), parsed)
+	a.Error(parsed.Parse("ToCR"))
+}
+
+func TestSymlinkLoopPolicy(t *testing.T) {
+	a := assert.New(t)
+
+	for _, policy := range []common.SymlinkLoopPolicy{common.ESymlinkLoopPolicy.Skip(), common.ESymlinkLoopPolicy.Error(), common.ESymlinkLoopPolicy.Ignore()} {
+		var parsed common.SymlinkLoopPolicy
+		a.NoError(parsed.Parse(policy.String()))
+		a.Equal(policy, parsed)
+	}
+
+	// skipping with a warning is the default
+	a.Equal("Skip", common.SymlinkLoopPolicy(0).String())
+	var parsed common.SymlinkLoopPolicy
+	a.NoError(parsed.Parse("error"))
+	a.Equal(common.ESymlinkLoopPolicy.Error(), parsed)
+	a.Error(parsed.Parse("Follow"))
+}
+
+func TestHTTPVersionPreference(t *testing.T) {
+	a := assert.New(t)
+
+	for _, preference := range []common.HTTPVersionPreference{common.EHTTPVersionPreference.Auto(), common.EHTTPVersionPreference.ForceHTTP1(), common.EHTTPVersionPreference.PreferHTTP2()} {
+		var parsed common.HTTPVersionPreference
+		a.NoError(parsed.Parse(preference.String()))
+		a.Equal(preference, parsed)
+	}
+
+	// the transport decides by default
+	a.Equal("Auto", common.HTTPVersionPreference(0).String())
+	var parsed common.HTTPVersionPreference
+	a.NoError(parsed.Parse("forcehttp1"))
+	a.Equal(common.EHTTPVersionPreference.ForceHTTP1(), parsed)
+	a.Error(parsed.Parse("HTTP3"))
+
+	transport := &http.Transport{}
+	common.EHTTPVersionPreference.ForceHTTP1().ConfigureTransport(transport)
+	a.False(transport.ForceAttemptHTTP2)
+	a.NotNil(transport.TLSNextProto)
+	a.Empty(transport.TLSNextProto)
+
+	transport = &http.Transport{}
+	common.EHTTPVersionPreference.PreferHTTP2().ConfigureTransport(transport)
+	a.True(transport.ForceAttemptHTTP2)
+	a.Nil(transport.TLSNextProto)
+
+	// Auto leaves the transport as it is
+	transport = &http.Transport{}
+	common.EHTTPVersionPreference.Auto().ConfigureTransport(transport)
+	a.Equal(&http.Transport{}, transport)
+}
+
+func TestLineEndingModeTranslate(t *testing.T) {
+	a := assert.New(t)
+	translate := func(mode common.LineEndingMode, s string) string {
+		var b strings.Builder
+		a.NoError(mode.Translate(&b, strings.NewReader(s)))
+		return b.String()
+	}
+
+	mixed := "a\r\nb\nc\rd\r\n\r\n"
+	a.Equal(mixed, translate(common.ELineEndingMode.None(), mixed))
+	// lone CRs are left alone
+	a.Equal("a\nb\nc\rd\n\n", translate(common.ELineEndingMode.ToLF(), mixed))
+	a.Equal("a\r\nb\r\nc\rd\r\n\r\n", translate(common.ELineEndingMode.ToCRLF(), mixed))
+
+	// translating twice changes nothing more
+	a.Equal("a\nb\n", translate(common.ELineEndingMode.ToLF(), translate(common.ELineEndingMode.ToLF(), "a\r\nb\n")))
+	a.Equal("a\r\nb\r\n", translate(common.ELineEndingMode.ToCRLF(), translate(common.ELineEndingMode.ToCRLF(), "a\r\nb\n")))
+
+	// a CRLF split across the reader's buffer is still seen whole
+	long := strings.Repeat("x", 4095) + "\r\n" + strings.Repeat("y", 5000) + "\r"
+	a.Equal(strings.Repeat("x", 4095)+"\n"+strings.Repeat("y", 5000)+"\r", translate(common.ELineEndingMode.ToLF(), long))
+	a.Equal("", translate(common.ELineEndingMode.ToCRLF(), ""))
+}
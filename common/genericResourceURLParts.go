@@ -95,10 +95,11 @@ func (g *GenericResourceURLParts) SetObjectName(objectName string) {
 	}
 }
 
+// String returns the full URL, credentials included, as the parsers of the Azure services' URLs do.
 func (g *GenericResourceURLParts) String() string {
 	switch g.location {
 	case ELocation.S3():
-		return g.s3URLParts.String()
+		return g.s3URLParts.UnmaskedString()
 	case ELocation.GCP():
 		return g.gcpURLParts.String()
 	case ELocation.Blob():
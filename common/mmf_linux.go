@@ -27,6 +27,8 @@ import (
 	"os"
 	"sync"
 	"syscall"
+
+	"golang.org/x/sys/unix"
 )
 
 const lineEnding = "\n"
@@ -87,6 +89,16 @@ func (m *MMF) UnuseMMF() {
 	m.lock.RUnlock()
 }
 
+// Flush synchronously writes the MMF's modified pages to its file.
+// It returns false if the MMF has already been unmapped.
+func (m *MMF) Flush() (bool, error) {
+	if !m.UseMMF() {
+		return false, nil
+	}
+	defer m.UnuseMMF()
+	return true, unix.Msync(m.slice, unix.MS_SYNC)
+}
+
 // Slice() returns the memory mapped byte slice
 func (m *MMF) Slice() []byte {
 	return m.slice
@@ -108,6 +108,17 @@ func (m *MMF) UnuseMMF() {
 	m.lock.RUnlock()
 }
 
+// Flush writes the MMF's modified pages to its file.
+// It returns false if the MMF has already been unmapped.
+func (m *MMF) Flush() (bool, error) {
+	if !m.UseMMF() {
+		return false, nil
+	}
+	defer m.UnuseMMF()
+	addr := uintptr(unsafe.Pointer(&(([]byte)(m.slice)[0])))
+	return true, syscall.FlushViewOfFile(addr, uintptr(m.length))
+}
+
 // Slice() returns the memory mapped byte slice
 func (m *MMF) Slice() []byte {
 	return m.slice
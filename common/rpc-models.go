@@ -1,9 +1,22 @@
 package common
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	blobsas "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"golang.org/x/net/http/httpguts"
 )
 
 // ResourceString represents a source or dest string, that can have
@@ -74,6 +87,34 @@ func (r ResourceString) addParamsToUrl(u *url.URL, sas, extraQuery string) {
 	}
 }
 
+// RedactedEndpoint returns the scheme and host of a remote resource, e.g. "https://myaccount.blob.core.windows.net".
+// Path, query and user info are dropped, so the result is safe to display. For S3, the bucket is stripped from
+// virtual-hosted-style hosts so that both addressing styles report the same service endpoint.
+// Local resources, or anything that doesn't parse as a URL, yield an empty string.
+func RedactedEndpoint(resource string, location Location) string {
+	if !location.IsRemote() {
+		return ""
+	}
+
+	u, err := url.Parse(resource)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	if location == ELocation.S3() {
+		if s3URLParts, err := NewS3URLParts(*u); err == nil {
+			return s3URLParts.Scheme + "://" + s3URLParts.Endpoint
+		}
+	}
+
+	return u.Scheme + "://" + u.Host
+}
+
+// RedactCommandString removes SAS signatures and other secrets from a command line, so that it can be persisted or displayed.
+func RedactCommandString(commandString string) string {
+	return NewAzCopyLogSanitizer().SanitizeLogMessage(commandString)
+}
+
 // Replace azcopy path separators (/) with the OS path separator
 func ConsolidatePathSeparators(path string) string {
 	pathSep := DeterminePathSeparator(path)
@@ -116,8 +157,9 @@ type CopyJobPartOrderRequest struct {
 	Transfers      Transfers
 	LogLevel       LogLevel
 	BlobAttributes BlobTransferAttributes
-	CommandString  string // commandString hold the user given command which is logged to the Job log file
+	CommandString  string // commandString hold the user given command which is logged to the Job log file. It's persisted redacted
 	CredentialInfo CredentialInfo
+	InvocationTime time.Time // when the user invoked the command; persisted along with the CommandString, for auditing
 
 	PreservePermissions            PreservePermissionsOption
 	PreserveInfo                   bool
@@ -137,6 +179,816 @@ type CopyJobPartOrderRequest struct {
 	S2SSourceCredentialType CredentialType // Only Anonymous and OAuth will really be used in response to this, but S3 and GCP will come along too...
 	FileAttributes          FileTransferAttributes
 	JobErrorHandler         JobErrorHandler
+
+	// FailFast cancels the remaining transfers as soon as any transfer fails, and reports the job as failed.
+	// It's not persisted in the plan files, so a resumed job will run to completion.
+	FailFast bool
+
+	// MetadataOnly applies the source's metadata (and the requested tier) to the existing destination objects,
+	// instead of copying their data. Only supported for remote sources and blob destinations; see Validate.
+	MetadataOnly bool
+
+	// PreserveEmptyDirectories materializes S3's directory markers (zero-byte objects whose key ends with '/') as folders
+	// at the destination. By default they're skipped. See IsEmptyDirMarker.
+	PreserveEmptyDirectories bool
+
+	// CompletionWebhookURL is POSTed the job's ListJobSummaryResponse (as JSON) once the job is done.
+	// When a secret is given, the body is signed with HMAC-SHA256, and the signature sent in the X-AzCopy-Signature header.
+	// Neither is persisted in the plan files, so resumed jobs don't notify.
+	CompletionWebhookURL    string
+	CompletionWebhookSecret SecretString
+
+	// RequesterPays signals S3 that the requester accepts the charges for reading the source,
+	// as a requester-pays bucket demands. Only supported for S3 sources; see Validate.
+	// Buckets and virtual directories can't be listed with it, so only single objects can be read this way.
+	RequesterPays bool
+
+	// CheckpointIntervalSeconds is how often the plan files are flushed to disk while the job runs,
+	// trading overhead for how much progress survives a crash. 0 leaves it to the OS.
+	CheckpointIntervalSeconds uint32
+
+	// MaxDepth, when set, is how deep below the source root enumeration goes, counted in delimiters (see KeyDepth):
+	// 0 means the root's immediate children only. Nil, or a negative depth, means there's no limit.
+	// Enumeration applies it, so it isn't persisted in the plan files.
+	MaxDepth *int32
+
+	// MaxEnumerationConcurrency caps how many listing calls enumerating the source makes at once, apart from the transfers'
+	// concurrency, for endpoints that throttle listings. 0 leaves it to the enumeration pool size.
+	// It only matters while enumerating, so it isn't persisted in the plan files.
+	MaxEnumerationConcurrency uint16
+
+	// OverwriteMtimeToleranceSeconds is how far apart the source's and destination's last modified times may be and still count
+	// as equal when ForceWrite is IfSourceNewer, since copies between clouds rarely keep them exact. See IsSourceNewer.
+	OverwriteMtimeToleranceSeconds uint32
+
+	// UserAgentSuffix is appended to AzCopy's user agent on the requests to Azure Storage, e.g. to tag a team's traffic
+	// for billing or monitoring. It can't have control characters, nor be longer than MaxUserAgentSuffixLength.
+	UserAgentSuffix string
+
+	// PreferServerSideCopy asks for the data to be copied by the services themselves rather than streamed through AzCopy.
+	// That's only possible between locations of the same provider (see CanServerSideCopy), so Validate rejects it for
+	// cross-provider copies, where it would be ignored. Copies between Azure services are always done server-side already.
+	PreferServerSideCopy bool
+
+	// ErrorOnReadDenied fails the transfers of local files AzCopy isn't permitted to read. By default they're skipped,
+	// with the status SkippedReadDenied, so that a few unreadable files don't fail the job. Only applies to local sources.
+	ErrorOnReadDenied bool
+
+	// SkipZeroByteObjects leaves out empty files while enumerating the source, e.g. the spurious marker objects some
+	// pipelines create; they're counted as skipped. See SkipsObject. Enumeration applies it, so it isn't persisted in the plan files.
+	SkipZeroByteObjects bool
+
+	// InventoryManifestURL points at the manifest.json of an S3 Inventory report of the source bucket. When set, the source
+	// is enumerated from the report's CSV files instead of being listed, which saves listing huge buckets again.
+	// Only applies to S3 sources. Enumeration applies it, so it isn't persisted in the plan files.
+	InventoryManifestURL string
+
+	// SourceRegion and DestinationRegion are the regions requests to S3 locations are signed for, when their URLs don't
+	// name one, e.g. for S3 compatible endpoints whose hosts don't follow AWS's naming. A region parsed from the URL wins;
+	// ValidationWarnings reports when the two disagree. See ResolveS3Region.
+	SourceRegion      string
+	DestinationRegion string
+
+	// OverwriteCheckMode is when the destinations' existence is checked before overwrite decisions. Skipping the checks
+	// saves a request per transfer, but then destinations are overwritten whatever ForceWrite says.
+	OverwriteCheckMode OverwriteCheckMode
+
+	// ChangeDetectionMode is how sync decides that a file changed since it was last transferred. MD5 relies on
+	// the source's hashes; ValidationWarnings reports sources that would have to compute them, which is slow.
+	ChangeDetectionMode ChangeDetectionMode
+
+	// CaseInsensitiveKeys makes FindDuplicateTransfers and SelfCopyTransfers ignore the case of object keys and paths,
+	// for S3 compatible backends that don't tell keys apart by case as AWS does.
+	// It only matters before the order is submitted, so it isn't persisted in the plan files.
+	CaseInsensitiveKeys bool
+
+	// IncludeByTag and ExcludeByTag select the source objects by their tags: an object is transferred when it has all of
+	// IncludeByTag's tags, with the same values, and none of ExcludeByTag's. See PassesTagFilters. Enumeration has to read
+	// every object's tags for them, which costs a GetObjectTagging request per object on top of the listing.
+	// Only S3 sources are supported. Enumeration applies them, so they aren't persisted in the plan files.
+	IncludeByTag map[string]string
+	ExcludeByTag map[string]string
+
+	// DestNameTemplate, when set, names the destinations of S3 objects by filling it in with RenderDestName,
+	// e.g. "{bucket}/archive/{key}", in place of their paths relative to the source. Their keys are those relative paths too.
+	// Names are decided at enumeration, so it isn't persisted in the plan files.
+	DestNameTemplate string
+
+	// MoveSource makes the engine delete each source file once it's been copied successfully, turning the copy into a move.
+	// Only copies within Azure Storage are supported, from sources that can be deleted; see readOnlySourceReason.
+	MoveSource bool
+
+	// MaxTransfers caps how many transfers the job has, e.g. to try a copy out on its first N objects; 0 leaves it unlimited.
+	// Enumeration stops once the cap is reached, see AllowsMoreTransfers, so it isn't persisted in the plan files.
+	MaxTransfers uint64
+
+	// FlattenDestination puts every file straight under the destination, for targets that can't handle deep prefixes:
+	// the '/' in their paths are replaced with FlattenSeparator, or DefaultFlattenSeparator when it's empty. See FlattenKey.
+	// Names are decided at enumeration, so neither is persisted in the plan files.
+	FlattenDestination bool
+	FlattenSeparator   string
+
+	// IncludeSnapshots and IncludeVersions ask for the source's snapshots and previous versions to be copied along with
+	// its current objects, e.g. so that a migration keeps their history. Only Azure sources have them.
+	// They decide what's enumerated, so they aren't persisted in the plan files.
+	IncludeSnapshots bool
+	IncludeVersions  bool
+
+	// SymlinkLoopPolicy is what enumerating a local source does when a followed symlink leads back to a directory that was
+	// already enumerated. It only applies when SymlinkHandlingType follows symlinks, and enumeration applies it, so it
+	// isn't persisted in the plan files.
+	SymlinkLoopPolicy SymlinkLoopPolicy
+
+	// HTTPVersionPreference is the HTTP version of the engine's connections to S3 sources, e.g. to keep to HTTP/1.1
+	// behind proxies that misbehave under HTTP/2. Only supported for S3 sources; see Validate.
+	HTTPVersionPreference HTTPVersionPreference
+}
+
+// DefaultFlattenSeparator replaces the '/' in the paths of flattened destinations, when FlattenSeparator isn't given.
+const DefaultFlattenSeparator = "_"
+
+// MaxS3RegionLength is the longest SourceRegion allowed, in bytes; it's also the room the plan files have for it.
+const MaxS3RegionLength = 64
+
+// MaxEnumerationConcurrencyLimit is the highest MaxEnumerationConcurrency allowed; far more than any endpoint needs.
+const MaxEnumerationConcurrencyLimit = 1024
+
+// IsSourceNewer reports whether the source was modified after the destination, by more than toleranceSec seconds.
+// Times within the tolerance of each other, either way, count as equal, so the source isn't newer.
+func IsSourceNewer(src, dst time.Time, toleranceSec uint32) bool {
+	return src.Sub(dst) > time.Duration(toleranceSec)*time.Second
+}
+
+// CanServerSideCopy reports whether data can be copied from src to dst by the storage provider itself,
+// without streaming it through AzCopy. Both must be remote and belong to the same provider, e.g. Blob and Azure Files.
+func CanServerSideCopy(src, dst Location) bool {
+	if !src.IsRemote() || !dst.IsRemote() {
+		return false
+	}
+	if src.IsAzure() || dst.IsAzure() {
+		return src.IsAzure() && dst.IsAzure()
+	}
+	return src == dst
+}
+
+// MaxUserAgentSuffixLength is the longest UserAgentSuffix allowed, in bytes; it's also the room the plan files have for it.
+const MaxUserAgentSuffixLength = 128
+
+// MinCheckpointIntervalSeconds is the shortest checkpoint interval allowed; flushing more often would thrash the disk.
+const MinCheckpointIntervalSeconds = 5
+
+// SecretString holds a credential. It's redacted whenever it's printed or serialized,
+// so the clear text can only be had by explicit conversion, i.e. string(s).
+type SecretString string
+
+const redactedSecret = "REDACTED"
+
+func (s SecretString) String() string {
+	if s == "" {
+		return ""
+	}
+	return redactedSecret
+}
+
+func (s SecretString) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+func (s SecretString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// engineManagedHeaders are set by AzCopy on its requests, and so can't be overridden by BlobTransferAttributes.CustomHeaders.
+// The keys are in canonical form. Neither can Azure Storage's x-ms-* headers be; see isEngineManagedHeader.
+var engineManagedHeaders = map[string]struct{}{
+	"Accept":              {},
+	"Authorization":       {},
+	"Connection":          {},
+	"Content-Length":      {},
+	"Content-Md5":         {},
+	"Content-Type":        {},
+	"Expect":              {},
+	"Host":                {},
+	"If-Match":            {},
+	"If-Modified-Since":   {},
+	"If-None-Match":       {},
+	"If-Unmodified-Since": {},
+	"Range":               {},
+	"Transfer-Encoding":   {},
+	"User-Agent":          {},
+}
+
+// isEngineManagedHeader reports whether AzCopy sets the header itself. Besides the engineManagedHeaders, that's any of
+// Azure Storage's x-ms-* headers, e.g. x-ms-copy-source or x-ms-range: the engine sets them, or they change what its
+// requests do.
+func isEngineManagedHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	_, managed := engineManagedHeaders[name]
+	return managed || strings.HasPrefix(name, "X-Ms-")
+}
+
+// Validate checks the order for combinations of options the engine cannot honour.
+// It returns the first of the problems ValidationErrors finds.
+func (r *CopyJobPartOrderRequest) Validate() error {
+	if errs := r.ValidationErrors(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidationErrors returns every problem with the order, rather than only the first: one per transfer that has any,
+// followed by the first problem with the order's options, if there's one.
+func (r *CopyJobPartOrderRequest) ValidationErrors() []JobError {
+	var errs []JobError
+	for i, transfer := range r.Transfers.List {
+		if err := r.validateTransfer(transfer); err != nil {
+			errs = append(errs, JobError{Index: i, Message: err.Error()})
+		}
+	}
+	if err := r.validateOptions(); err != nil {
+		errs = append(errs, JobError{Index: NoTransferIndex, Message: err.Error()})
+	}
+	return errs
+}
+
+// ValidateOptions checks the order's options, without going through its transfers one by one as Validate does.
+// The options are the same for every part of a job, so the engine only checks them once, on its first part.
+func (r *CopyJobPartOrderRequest) ValidateOptions() error {
+	return r.validateOptions()
+}
+
+// PreservesPOSIXProperties reports whether the sources' POSIX properties are preserved, as asked by either
+// PreservePOSIXProperties or BlobAttributes.PreservePosixProperties.
+func (r *CopyJobPartOrderRequest) PreservesPOSIXProperties() bool {
+	return r.PreservePOSIXProperties || r.BlobAttributes.PreservePosixProperties
+}
+
+// ValidationWarnings returns the problems with the order that don't keep it from running, e.g. options that are ignored.
+func (r *CopyJobPartOrderRequest) ValidationWarnings() []string {
+	var warnings []string
+	if warning := s3RegionWarning("source", r.SourceRoot, r.FromTo.From(), r.SourceRegion); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if warning := s3RegionWarning("destination", r.DestinationRoot, r.FromTo.To(), r.DestinationRegion); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if r.ChangeDetectionMode == EChangeDetectionMode.MD5() && !r.FromTo.From().ProvidesMD5Cheaply() {
+		warnings = append(warnings, fmt.Sprintf("%s sources don't provide MD5 hashes cheaply, so detecting changes by MD5 may be slow or fall back to modification times", r.FromTo.From()))
+	}
+	return warnings
+}
+
+// s3RegionWarning warns of an explicit region that's ignored, either because the location isn't S3 or because its URL names another region.
+func s3RegionWarning(end string, root ResourceString, location Location, region string) string {
+	if region == "" {
+		return ""
+	}
+	if location != ELocation.S3() {
+		return fmt.Sprintf("the %s region %s only applies to S3 locations, it's ignored for %s ones", end, region, location)
+	}
+	if parts, err := NewS3URLPartsFromString(root.Value); err == nil && parts.Region != "" && !strings.EqualFold(parts.Region, region) {
+		return fmt.Sprintf("the %s region %s disagrees with the region %s of its URL, which is used instead", end, region, parts.Region)
+	}
+	return ""
+}
+
+func (r *CopyJobPartOrderRequest) validateTransfer(transfer CopyTransfer) error {
+	if !transferPathFitsLocation(transfer.Source, r.FromTo.From()) {
+		return fmt.Errorf("the source %s is not a %s location", URLStringExtension(transfer.Source).RedactSecretQueryParamForLogging(), r.FromTo.From())
+	}
+	if !transferPathFitsLocation(transfer.Destination, r.FromTo.To()) {
+		return fmt.Errorf("the destination %s is not a %s location", URLStringExtension(transfer.Destination).RedactSecretQueryParamForLogging(), r.FromTo.To())
+	}
+	if rng := transfer.Range; rng != nil {
+		if rng.Start < 0 || (rng.End < 0 && rng.End != TransferRangeToEnd) {
+			return fmt.Errorf("the range %d-%d is invalid", rng.Start, rng.End)
+		}
+		if rng.End != TransferRangeToEnd && rng.Start > rng.End {
+			return fmt.Errorf("the range starts at %d, after its end at %d", rng.Start, rng.End)
+		}
+	}
+	return nil
+}
+
+func (r *CopyJobPartOrderRequest) validateOptions() error {
+	if r.MetadataOnly {
+		if !r.FromTo.From().IsRemote() {
+			return fmt.Errorf("metadata only transfers are not supported from %s sources", r.FromTo.From())
+		}
+		if to := r.FromTo.To(); to != ELocation.Blob() && to != ELocation.BlobFS() {
+			return fmt.Errorf("metadata only transfers are not supported to %s destinations", to)
+		}
+	}
+
+	for name := range r.BlobAttributes.CustomHeaders {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("custom header names cannot be empty")
+		}
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("the custom header name %q is not valid", name)
+		}
+		if isEngineManagedHeader(name) {
+			return fmt.Errorf("the custom header %s conflicts with a header set by AzCopy", name)
+		}
+	}
+	if n := len(r.BlobAttributes.EncodedCustomHeaders()); n > MaxCustomHeadersBytes {
+		return fmt.Errorf("the custom headers take %d bytes once encoded, over the limit of %d", n, MaxCustomHeadersBytes)
+	}
+
+	normalizedExtensions := make(map[string]bool, len(r.BlobAttributes.BlobTypeOverridesByExtension))
+	for ext := range r.BlobAttributes.BlobTypeOverridesByExtension {
+		normalized := normalizeExtension(strings.TrimSpace(ext))
+		if normalized == "." {
+			return errors.New("blob type override extensions cannot be empty")
+		}
+		if normalized != normalizeExtension(ext) || strings.ContainsAny(normalized[1:], `./\`) {
+			return fmt.Errorf("the blob type override extension %q is not a file extension", ext)
+		}
+		if normalizedExtensions[normalized] {
+			return fmt.Errorf("the blob type for extension %s is overridden more than once", normalized)
+		}
+		normalizedExtensions[normalized] = true
+	}
+	if n := len(r.BlobAttributes.EncodedBlobTypeOverrides()); n > MaxBlobTypeOverridesBytes {
+		return fmt.Errorf("the blob type overrides take %d bytes once encoded, over the limit of %d", n, MaxBlobTypeOverridesBytes)
+	}
+
+	if threshold := r.BlobAttributes.SingleShotThresholdBytes; threshold != 0 {
+		if threshold < 0 {
+			return fmt.Errorf("the single-shot threshold cannot be negative, got %d", threshold)
+		}
+		if threshold < r.BlobAttributes.BlockSizeInBytes {
+			return fmt.Errorf("the single-shot threshold of %d bytes is below the block size of %d bytes", threshold, r.BlobAttributes.BlockSizeInBytes)
+		}
+		if r.BlobAttributes.PutBlobSizeInBytes != 0 && r.BlobAttributes.PutBlobSizeInBytes != threshold {
+			return errors.New("the single-shot threshold and the put blob size are the same setting, only one of them can be given")
+		}
+	}
+
+	if r.FiltersByTag() {
+		if r.FromTo.From() != ELocation.S3() {
+			return fmt.Errorf("filtering by tags only applies to S3 sources, not %s ones", r.FromTo.From())
+		}
+		for _, tags := range []map[string]string{r.IncludeByTag, r.ExcludeByTag} {
+			if _, ok := tags[""]; ok {
+				return errors.New("the tags filtered by cannot have empty keys")
+			}
+		}
+	}
+
+	if r.DestNameTemplate != "" {
+		if r.FromTo.From() != ELocation.S3() {
+			return fmt.Errorf("destination name templates only apply to S3 sources, not %s ones", r.FromTo.From())
+		}
+		if _, err := RenderDestName(r.DestNameTemplate, nil); err != nil {
+			return err
+		}
+	}
+
+	if r.MoveSource {
+		if !r.FromTo.From().IsAzure() || !r.FromTo.To().IsAzure() {
+			return fmt.Errorf("moving only applies to copies within Azure Storage, not %s ones", r.FromTo)
+		}
+		if reason := r.readOnlySourceReason(); reason != "" {
+			return fmt.Errorf("the sources cannot be moved, %s", reason)
+		}
+	}
+
+	if (r.IncludeSnapshots || r.IncludeVersions) && !r.FromTo.From().IsAzure() {
+		return fmt.Errorf("snapshots and versions are only included from Azure sources, not %s ones", r.FromTo.From())
+	}
+
+	if r.SymlinkLoopPolicy != ESymlinkLoopPolicy.Skip() && !r.SymlinkHandlingType.Follow() {
+		return fmt.Errorf("the symlink loop policy %s only applies when following symlinks", r.SymlinkLoopPolicy)
+	}
+
+	if r.FlattenDestination {
+		if strings.ContainsAny(r.FlattenSeparator, `/\`) {
+			return fmt.Errorf("the flattening separator %q cannot contain path separators", r.FlattenSeparator)
+		}
+		if r.DestNameTemplate != "" {
+			return errors.New("destination name templates and flattening cannot be combined, the template can name the destinations flat itself")
+		}
+	} else if r.FlattenSeparator != "" {
+		return errors.New("the flattening separator only applies when flattening the destination")
+	}
+
+	if r.BlobAttributes.LineEndingMode != ELineEndingMode.None() {
+		if r.FromTo.From() != ELocation.Local() {
+			return fmt.Errorf("line-ending translation only applies to uploads of local files, not %s transfers", r.FromTo)
+		}
+		if r.BlobAttributes.CompressOnUpload {
+			// the line endings would have to be translated before compressing, which is easily mistaken for the other way round
+			return errors.New("line-ending translation cannot be combined with compression on upload")
+		}
+		if len(r.BlobAttributes.TextExtensions) == 0 {
+			return errors.New("line-ending translation needs the extensions of the text files to translate")
+		}
+	} else if len(r.BlobAttributes.TextExtensions) != 0 {
+		return errors.New("text extensions only apply when translating line endings")
+	}
+	for _, ext := range r.BlobAttributes.TextExtensions {
+		normalized := normalizeExtension(strings.TrimSpace(ext))
+		if normalized == "." {
+			return errors.New("text extensions cannot be empty")
+		}
+		if normalized != normalizeExtension(ext) || strings.ContainsAny(normalized[1:], `./\,`) {
+			return fmt.Errorf("the text extension %q is not a file extension", ext)
+		}
+	}
+	if n := len(r.BlobAttributes.EncodedTextExtensions()); n > MaxTextExtensionsBytes {
+		return fmt.Errorf("the text extensions take %d bytes once encoded, over the limit of %d", n, MaxTextExtensionsBytes)
+	}
+
+	if r.BlobAttributes.CompressOnUpload {
+		if r.FromTo != EFromTo.LocalBlob() {
+			return fmt.Errorf("compression on upload is only supported for uploads to Blob storage, not %s transfers", r.FromTo)
+		}
+		if r.PreservesPOSIXProperties() {
+			return errors.New("compression on upload cannot preserve POSIX properties")
+		}
+		if r.BlobAttributes.ContentEncoding != "" {
+			return fmt.Errorf("cannot compress content whose encoding is already set to %s", r.BlobAttributes.ContentEncoding)
+		}
+	}
+
+	if r.BlobAttributes.PreservePosixProperties {
+		switch to := r.FromTo.To(); to {
+		case ELocation.Local(), ELocation.FileNFS():
+			if r.BlobAttributes.MapToMetadata {
+				return fmt.Errorf("%s destinations keep POSIX properties natively, they can't be mapped to metadata", to)
+			}
+		case ELocation.Blob(), ELocation.BlobFS():
+			if !r.BlobAttributes.MapToMetadata {
+				return fmt.Errorf("%s destinations can only keep POSIX properties as metadata, which MapToMetadata must be set for", to)
+			}
+		default:
+			return fmt.Errorf("POSIX properties cannot be preserved on %s destinations", to)
+		}
+	} else if r.BlobAttributes.MapToMetadata {
+		return errors.New("mapping to metadata only applies when preserving POSIX properties")
+	}
+
+	if r.BlobAttributes.PreserveSourceTimeAsMetadata && !(r.FromTo.From().IsRemote() && r.FromTo.To().IsRemote()) {
+		return fmt.Errorf("preserving the source's time as metadata only applies to copies between services, it would be ignored for %s transfers", r.FromTo)
+	}
+
+	if r.BlobAttributes.PreserveTags && r.FromTo != EFromTo.S3Blob() {
+		return fmt.Errorf("preserving tags is only supported when copying from S3 to Blob storage, not for %s transfers", r.FromTo)
+	}
+	if len(r.BlobAttributes.Tags) > 0 {
+		if r.BlobAttributes.BlobTagsString != "" {
+			return errors.New("tags cannot be given both as a map and as a string")
+		}
+		if err := validateS3Tags(r.BlobAttributes.Tags); err != nil {
+			return err
+		}
+	}
+	if n := len(r.BlobAttributes.EncodedTags()); n > MaxBlobTagsBytes {
+		return fmt.Errorf("the tags take %d bytes once encoded, over the limit of %d", n, MaxBlobTagsBytes)
+	}
+
+	if r.BlobAttributes.MapStorageClassToTier && r.FromTo.From() != ELocation.S3() {
+		return fmt.Errorf("storage classes can only be mapped to tiers for S3 sources, not %s ones", r.FromTo.From())
+	}
+
+	if r.BlobAttributes.MetadataKeyCasePolicy != EMetadataKeyCasePolicy.Preserve() && r.FromTo.From() != ELocation.S3() {
+		return fmt.Errorf("the metadata key case policy %s only applies to S3 sources, not %s ones", r.BlobAttributes.MetadataKeyCasePolicy, r.FromTo.From())
+	}
+
+	if r.RequesterPays && r.FromTo.From() != ELocation.S3() {
+		return fmt.Errorf("requester pays is not supported for %s sources", r.FromTo.From())
+	}
+
+	if r.HTTPVersionPreference != EHTTPVersionPreference.Auto() && r.FromTo.From() != ELocation.S3() {
+		return fmt.Errorf("the HTTP version preference %s only applies to S3 sources, not %s ones", r.HTTPVersionPreference, r.FromTo.From())
+	}
+
+	if r.PreferServerSideCopy && !CanServerSideCopy(r.FromTo.From(), r.FromTo.To()) {
+		return fmt.Errorf("server-side copy is only possible between locations of the same provider, it would be ignored for %s transfers", r.FromTo)
+	}
+
+	if r.InventoryManifestURL != "" {
+		if r.FromTo.From() != ELocation.S3() {
+			return fmt.Errorf("inventory manifests can only be enumerated for S3 sources, not %s ones", r.FromTo.From())
+		}
+		if _, err := NewS3URLPartsFromString(r.InventoryManifestURL); err != nil {
+			return fmt.Errorf("the inventory manifest URL is not a valid S3 URL: %w", err)
+		}
+	}
+
+	if len(r.SourceRegion) > MaxS3RegionLength {
+		return fmt.Errorf("the source region is %d bytes long, over the limit of %d", len(r.SourceRegion), MaxS3RegionLength)
+	}
+
+	if r.ErrorOnReadDenied && r.FromTo.From() != ELocation.Local() {
+		return fmt.Errorf("failing on files that can't be read only applies to local sources, not %s ones", r.FromTo.From())
+	}
+
+	if r.CheckpointIntervalSeconds != 0 && r.CheckpointIntervalSeconds < MinCheckpointIntervalSeconds {
+		return fmt.Errorf("the checkpoint interval must be at least %d seconds, got %d", MinCheckpointIntervalSeconds, r.CheckpointIntervalSeconds)
+	}
+
+	if r.OverwriteMtimeToleranceSeconds != 0 && r.ForceWrite != EOverwriteOption.IfSourceNewer() {
+		return fmt.Errorf("a last modified time tolerance only applies when overwriting if the source is newer, not with overwrite %s", r.ForceWrite)
+	}
+
+	if len(r.UserAgentSuffix) > MaxUserAgentSuffixLength {
+		return fmt.Errorf("the user agent suffix is %d bytes long, over the limit of %d", len(r.UserAgentSuffix), MaxUserAgentSuffixLength)
+	}
+	if strings.IndexFunc(r.UserAgentSuffix, unicode.IsControl) != -1 {
+		return errors.New("the user agent suffix cannot contain control characters")
+	}
+
+	if r.MaxEnumerationConcurrency > MaxEnumerationConcurrencyLimit {
+		return fmt.Errorf("the enumeration concurrency can be at most %d, got %d", MaxEnumerationConcurrencyLimit, r.MaxEnumerationConcurrency)
+	}
+
+	if r.CompletionWebhookURL != "" || r.CompletionWebhookSecret != "" {
+		u, err := url.Parse(r.CompletionWebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("the completion webhook must be a valid http or https URL")
+		}
+	}
+
+	return nil
+}
+
+var windowsAbsolutePathRegex = regexp.MustCompile(`^([A-Za-z]:[\\/]|\\\\)`)
+
+// FindDuplicateTransfers returns the indices of the transfers that repeat an earlier transfer's source and destination,
+// so that they can be dropped before the order is submitted. Paths are compared as canonicalTransferPath has them,
+// with their case folded if CaseInsensitiveKeys is set.
+func (r CopyJobPartOrderRequest) FindDuplicateTransfers() []int {
+	var duplicates []int
+	seen := make(map[[2]string]struct{}, len(r.Transfers.List))
+	for i, transfer := range r.Transfers.List {
+		key := [2]string{canonicalTransferPath(transfer.Source, r.CaseInsensitiveKeys), canonicalTransferPath(transfer.Destination, r.CaseInsensitiveKeys)}
+		if _, ok := seen[key]; ok {
+			duplicates = append(duplicates, i)
+			continue
+		}
+		seen[key] = struct{}{}
+	}
+	return duplicates
+}
+
+// FlattenKey joins the segments of a file's path with sep instead of '/', e.g. "a/b/c.txt" with "_" is "a_b_c.txt",
+// so that it can be written straight under the destination. Leading and trailing separators are dropped.
+// Distinct paths may flatten to the same name, e.g. "a/b" and "a_b" both give "a_b"; FindDestinationCollisions finds them.
+func FlattenKey(key, sep string) string {
+	return strings.ReplaceAll(strings.Trim(key, "/"), "/", sep)
+}
+
+// FindDestinationCollisions returns the destinations that more than one source is transferred to, each with the
+// indices of its transfers, so that the front end can warn that all but one source would be clobbered. The destinations
+// are keyed as the first of their transfers has them. Paths are compared as FindDuplicateTransfers compares them,
+// and duplicates of the same transfer don't collide.
+func (r CopyJobPartOrderRequest) FindDestinationCollisions() map[string][]int {
+	type destination struct {
+		path    string
+		indices []int
+		sources map[string]struct{}
+	}
+	destinations := map[string]*destination{}
+	for i, transfer := range r.Transfers.List {
+		key := canonicalTransferPath(transfer.Destination, r.CaseInsensitiveKeys)
+		d, ok := destinations[key]
+		if !ok {
+			d = &destination{path: transfer.Destination, sources: map[string]struct{}{}}
+			destinations[key] = d
+		}
+		d.indices = append(d.indices, i)
+		d.sources[canonicalTransferPath(transfer.Source, r.CaseInsensitiveKeys)] = struct{}{}
+	}
+
+	collisions := map[string][]int{}
+	for _, d := range destinations {
+		if len(d.sources) > 1 {
+			collisions[d.path] = d.indices
+		}
+	}
+	return collisions
+}
+
+// SelfCopyTransfers returns the indices of the transfers whose source is their own destination, which is usually a mistake,
+// or a way to update the properties only. The transfers' paths are resolved against the order's roots, then compared
+// as FindDuplicateTransfers compares them. A transfer from a version or snapshot isn't a self-copy, since it restores it.
+func (r CopyJobPartOrderRequest) SelfCopyTransfers() []int {
+	var selfCopies []int
+	sourceRootIsVersion := queryNamesVersion(r.SourceRoot.ExtraQuery)
+	for i, transfer := range r.Transfers.List {
+		if sourceRootIsVersion || transfer.BlobVersionID != "" || transfer.BlobSnapshotID != "" {
+			continue
+		}
+		source := GenerateFullPath(r.SourceRoot.Value, transfer.Source)
+		if _, query, _ := strings.Cut(source, "?"); queryNamesVersion(query) {
+			continue
+		}
+		if canonicalTransferPath(source, r.CaseInsensitiveKeys) == canonicalTransferPath(GenerateFullPath(r.DestinationRoot.Value, transfer.Destination), r.CaseInsensitiveKeys) {
+			selfCopies = append(selfCopies, i)
+		}
+	}
+	return selfCopies
+}
+
+// queryNamesVersion reports whether a URL's query picks a version or snapshot of its object, as S3's and Blob's do.
+func queryNamesVersion(query string) bool {
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return false
+	}
+	_, _, versioned := caseInsensitiveValues(values).Get(versionQueryParamKey)
+	_, _, snapshot := caseInsensitiveValues(values).Get("snapshot")
+	return versioned || snapshot
+}
+
+// readOnlySourceReason tells why the order's sources can't be deleted, as MoveSource needs, or returns "" when they can.
+// Versions and snapshots are read-only, as are sources accessed through a SAS without the delete permission.
+func (r *CopyJobPartOrderRequest) readOnlySourceReason() string {
+	values, _ := url.ParseQuery(strings.TrimPrefix(r.SourceRoot.ExtraQuery, "?"))
+	if _, _, shareSnapshot := caseInsensitiveValues(values).Get("sharesnapshot"); shareSnapshot || queryNamesVersion(r.SourceRoot.ExtraQuery) {
+		return "they're in a version or snapshot"
+	}
+	for _, transfer := range r.Transfers.List {
+		if transfer.BlobVersionID != "" || transfer.BlobSnapshotID != "" {
+			return fmt.Sprintf("%s is a version or snapshot", transfer.Source)
+		}
+	}
+	if r.SourceRoot.SAS != "" {
+		sas, err := url.ParseQuery(strings.TrimPrefix(r.SourceRoot.SAS, "?"))
+		if err == nil && !strings.Contains(sas.Get("sp"), "d") {
+			return "the source's SAS doesn't grant the delete permission"
+		}
+	}
+	return ""
+}
+
+// TotalSourceBytes sums the sizes of the order's transfers, e.g. to know the bytes expected before the job starts.
+// Sizes that aren't known, e.g. those of streamed sources, count as 0.
+func (r CopyJobPartOrderRequest) TotalSourceBytes() int64 {
+	var total int64
+	for _, transfer := range r.Transfers.List {
+		if transfer.SourceSize > 0 {
+			total += transfer.SourceSize
+		}
+	}
+	return total
+}
+
+// canonicalTransferPath returns the form of path that's equal for all the spellings of the same resource.
+// URLs' schemes and hosts are case-insensitive, their paths are compared decoded, and their queries (e.g. SAS tokens) are ignored.
+// Windows absolute paths are case-insensitive. Anything else, e.g. an object key, is case-sensitive and kept as is,
+// unless foldCase is set, which makes the whole path case-insensitive.
+func canonicalTransferPath(path string, foldCase bool) string {
+	if foldCase {
+		path = strings.ToLower(path)
+	}
+	if windowsAbsolutePathRegex.MatchString(path) {
+		return strings.ToLower(path)
+	}
+	u, err := url.Parse(path)
+	if err != nil || u.Host == "" || !strings.EqualFold(u.Scheme, "http") && !strings.EqualFold(u.Scheme, "https") {
+		return path
+	}
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + u.Path
+}
+
+// transferPathFitsLocation reports whether a transfer's path could belong to location.
+// Transfers' paths are relative to the job's roots, so only the paths that are evidently absolute are checked:
+// an http(s) URL can't be local, and must point at the location's service if it's recognizable,
+// while a Windows absolute path (e.g. C:\dir or \\server\share) can only be local.
+func transferPathFitsLocation(path string, location Location) bool {
+	switch location {
+	case ELocation.Unknown(), ELocation.None(), ELocation.Benchmark(), ELocation.Pipe():
+		return true
+	}
+
+	if windowsAbsolutePathRegex.MatchString(path) {
+		return !location.IsRemote()
+	}
+
+	u, err := url.Parse(path)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return true // relative
+	}
+	if !location.IsRemote() {
+		return false
+	}
+
+	var urlLocation Location
+	switch host := strings.ToLower(u.Host); {
+	case strings.Contains(host, ".blob."), strings.Contains(host, ".dfs."):
+		urlLocation = ELocation.Blob()
+	case strings.Contains(host, ".file."):
+		urlLocation = ELocation.File()
+	case IsS3URL(*u):
+		urlLocation = ELocation.S3()
+	case IsGCPURL(*u):
+		urlLocation = ELocation.GCP()
+	default:
+		return true // e.g. an emulator or a custom domain
+	}
+
+	// Blob and BlobFS share accounts, as do the SMB and NFS flavours of Files
+	switch location {
+	case ELocation.BlobFS():
+		location = ELocation.Blob()
+	case ELocation.FileNFS():
+		location = ELocation.File()
+	}
+	return urlLocation == location
+}
+
+// FiltersByTag reports whether the source objects are selected by their tags, i.e. whether enumeration must read them.
+func (r CopyJobPartOrderRequest) FiltersByTag() bool {
+	return len(r.IncludeByTag) != 0 || len(r.ExcludeByTag) != 0
+}
+
+// PassesTagFilters reports whether an object with the given tags is transferred, going by IncludeByTag and ExcludeByTag.
+// Tags' keys and values are matched exactly, as S3 compares them.
+func (r CopyJobPartOrderRequest) PassesTagFilters(tags map[string]string) bool {
+	for key, value := range r.IncludeByTag {
+		if tagValue, ok := tags[key]; !ok || tagValue != value {
+			return false
+		}
+	}
+	for key, value := range r.ExcludeByTag {
+		if tagValue, ok := tags[key]; ok && tagValue == value {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowsMoreTransfers reports whether a transfer can be added to the job after scheduled ones, for MaxTransfers.
+func (r CopyJobPartOrderRequest) AllowsMoreTransfers(scheduled uint64) bool {
+	return r.MaxTransfers == 0 || scheduled < r.MaxTransfers
+}
+
+// SkipsObject reports whether enumeration leaves out an object of the given type and size, for SkipZeroByteObjects.
+// Only files are skipped, since folders have no size.
+func (r CopyJobPartOrderRequest) SkipsObject(entityType EntityType, size int64) bool {
+	return r.SkipZeroByteObjects && entityType == EEntityType.File() && size == 0
+}
+
+// DestSASExpiry returns the expiry time (se) of the SAS on the destination, so that callers can warn
+// about an already-expired SAS before starting the job. ok is false when the destination is not Azure
+// or carries no SAS expiry.
+func (r CopyJobPartOrderRequest) DestSASExpiry() (expiry time.Time, ok bool) {
+	if !r.FromTo.To().IsAzure() {
+		return time.Time{}, false
+	}
+	u, err := r.DestinationRoot.FullURL()
+	if err != nil {
+		return time.Time{}, false
+	}
+	parts, err := blobsas.ParseURL(u.String())
+	if err != nil {
+		return time.Time{}, false
+	}
+	expiry = parts.SAS.ExpiryTime()
+	return expiry, !expiry.IsZero()
+}
+
+// ValidatePartSequence checks that parts make up a single job's complete submission:
+// they share the JobID, their part numbers run from 0 without gaps or repeats (in any order),
+// and exactly one of them, the highest numbered, is the final part.
+func ValidatePartSequence(parts []CopyJobPartOrderRequest) error {
+	if len(parts) == 0 {
+		return errors.New("no job parts were given")
+	}
+
+	seen := make([]bool, len(parts))
+	finalPart := -1
+	for _, part := range parts {
+		if part.JobID != parts[0].JobID {
+			return fmt.Errorf("part %d belongs to job %s, not %s", part.PartNum, part.JobID, parts[0].JobID)
+		}
+		if int(part.PartNum) >= len(parts) {
+			return fmt.Errorf("part %d is out of sequence: %d parts should be numbered 0 to %d", part.PartNum, len(parts), len(parts)-1)
+		}
+		if seen[part.PartNum] {
+			return fmt.Errorf("part %d is given more than once", part.PartNum)
+		}
+		seen[part.PartNum] = true
+
+		if part.IsFinalPart {
+			if finalPart != -1 {
+				return fmt.Errorf("parts %d and %d are both marked as the final part", finalPart, part.PartNum)
+			}
+			finalPart = int(part.PartNum)
+		}
+	}
+
+	// with no repeats and nothing beyond len(parts)-1, every part number is accounted for
+	if finalPart == -1 {
+		return errors.New("no part is marked as the final part")
+	}
+	if finalPart != len(parts)-1 {
+		return fmt.Errorf("part %d is marked as the final part, but part %d follows it", finalPart, len(parts)-1)
+	}
+	return nil
 }
 
 // CredentialInfo contains essential credential info which need be transited between modules,
@@ -155,6 +1007,10 @@ type GCPCredentialInfo struct {
 type S3CredentialInfo struct {
 	Endpoint string
 	Region   string
+	// PathStyle forces path-style requests, for endpoints that can't address buckets as virtual hosts; see S3URLParts.RequiresPathStyle
+	PathStyle bool
+	// HTTPVersion is the HTTP version the client's connections use
+	HTTPVersion HTTPVersionPreference
 }
 
 type CopyJobPartOrderErrorType string
@@ -168,6 +1024,34 @@ func (CopyJobPartOrderErrorType) NoTransfersScheduledErr() CopyJobPartOrderError
 type CopyJobPartOrderResponse struct {
 	ErrorMsg   CopyJobPartOrderErrorType
 	JobStarted bool
+
+	// Errors lists each problem that kept the job from starting; ErrorMsg joins them, for older front-ends
+	Errors []JobError
+}
+
+// NoTransferIndex is the JobError.Index of problems with the order as a whole, rather than one of its transfers.
+const NoTransferIndex = -1
+
+// JobError is a problem with a job part order. Index is that of the transfer at fault, or NoTransferIndex.
+type JobError struct {
+	Index   int
+	Message string
+}
+
+func (e JobError) Error() string {
+	if e.Index == NoTransferIndex {
+		return e.Message
+	}
+	return fmt.Sprintf("transfer %d: %s", e.Index, e.Message)
+}
+
+// NewCopyJobPartOrderErrorResponse reports that the job didn't start because of errs, each of them listed in Errors.
+func NewCopyJobPartOrderErrorResponse(errs []JobError) CopyJobPartOrderResponse {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return CopyJobPartOrderResponse{ErrorMsg: CopyJobPartOrderErrorType(strings.Join(messages, "; ")), Errors: errs}
 }
 
 // This struct represents the optional attribute for blob request header
@@ -192,6 +1076,206 @@ type BlobTransferAttributes struct {
 	PermanentDeleteOption            PermanentDeleteOption // Permanently deletes soft-deleted snapshots when indicated by user
 	RehydratePriority                RehydratePriorityType // rehydrate priority of blob
 	DeleteDestinationFileIfNecessary bool                  // deletes the dst blob if indicated
+	CustomHeaders                    map[string]string     // extra headers sent with each of the transfers' requests to the destination; see Validate
+	CompressOnUpload                 bool                  // when uploading to blobs, compress the files and set their content encoding accordingly
+	CompressionAlgorithm             CompressionAlgorithm  // how CompressOnUpload compresses; None means Gzip
+	PreserveTags                     bool                  // when copying from S3, carry the objects' tags over as blob tags
+	Tags                             map[string]string     // tags set on every destination, in place of any preserved ones
+	PreserveSourceTimeAsMetadata     bool                  // when copying between services, record the source's last modified time in the destination's metadata
+	BlobTypeOverridesByExtension     map[string]BlobType   // blob types chosen by file extension, in place of BlobType; see BlobTypeForName
+	MapStorageClassToTier            bool                  // when copying from S3, give the destinations the tiers their storage classes map to; see StorageClassToBlobTier
+	MetadataKeyCasePolicy            MetadataKeyCasePolicy // when copying from S3, how metadata keys differing only by case are carried over
+	PreserveContentDisposition       bool                  // when copying between services, carry the source's content disposition over; see ResolveContentDisposition
+	SingleShotThresholdBytes         int64                 // when uploading, files smaller than this are uploaded in a single request; 0 leaves it to PutBlobSizeInBytes
+	PreservePosixProperties          bool                  // keep the sources' POSIX owner, group and mode; see CopyJobPartOrderRequest.PreservesPOSIXProperties
+	MapToMetadata                    bool                  // with PreservePosixProperties, store them as metadata on destinations that can't hold them natively, i.e. blobs
+	LineEndingMode                   LineEndingMode        // when uploading, how the line endings of the files with TextExtensions are translated
+	TextExtensions                   []string              // the extensions of the files whose line endings are translated; see HasTextExtension
+	MaxPerTransferRetries            uint32                // how many times all of a transfer's requests may be retried in total before it fails; 0 leaves it to the engine
+}
+
+// SourceLastModifiedMetadataKey is the metadata key PreserveSourceTimeAsMetadata records the source's last modified time under,
+// formatted as RFC 3339. Azure requires metadata names to be C# identifiers, hence the underscores.
+const SourceLastModifiedMetadataKey = "source_last_modified"
+
+// MaxCustomHeadersBytes is the room the plan files have for the URL encoded custom headers.
+const MaxCustomHeadersBytes = 1000
+
+// EncodedCustomHeaders returns the custom headers URL encoded, as they're stored in the plan files.
+func (a BlobTransferAttributes) EncodedCustomHeaders() string {
+	customHeaders := url.Values{}
+	for name, value := range a.CustomHeaders {
+		customHeaders.Set(name, value)
+	}
+	return customHeaders.Encode()
+}
+
+// MaxBlobTypeOverridesBytes is the room the plan files have for the URL encoded blob type overrides.
+const MaxBlobTypeOverridesBytes = 256
+
+// EncodedBlobTypeOverrides returns the blob type overrides URL encoded, as they're stored in the plan files.
+// Extensions are stored lower case, with their leading dot.
+func (a BlobTransferAttributes) EncodedBlobTypeOverrides() string {
+	overrides := url.Values{}
+	for ext, blobType := range a.BlobTypeOverridesByExtension {
+		overrides.Set(normalizeExtension(ext), blobType.String())
+	}
+	return overrides.Encode()
+}
+
+// ParseBlobTypeOverrides decodes blob type overrides encoded by EncodedBlobTypeOverrides.
+func ParseBlobTypeOverrides(encoded string) (map[string]BlobType, error) {
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[string]BlobType, len(values))
+	for ext := range values {
+		var blobType BlobType
+		if err := blobType.Parse(values.Get(ext)); err != nil {
+			return nil, err
+		}
+		overrides[ext] = blobType
+	}
+	return overrides, nil
+}
+
+// BlobTypeForName returns the blob type overridden for the extension of name, or fallback if there's no override for it.
+// Extensions are matched case-insensitively, and can be given with or without their leading dot.
+func BlobTypeForName(overrides map[string]BlobType, name string, fallback BlobType) BlobType {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == "" {
+		return fallback
+	}
+	for key, blobType := range overrides {
+		if normalizeExtension(key) == ext {
+			return blobType
+		}
+	}
+	return fallback
+}
+
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// MaxTextExtensionsBytes is the room the plan files have for the encoded text extensions.
+const MaxTextExtensionsBytes = 256
+
+// EncodedTextExtensions returns the text extensions as they're stored in the plan files: lower case, with their leading dot,
+// and separated by commas.
+func (a BlobTransferAttributes) EncodedTextExtensions() string {
+	extensions := make([]string, len(a.TextExtensions))
+	for i, ext := range a.TextExtensions {
+		extensions[i] = normalizeExtension(ext)
+	}
+	return strings.Join(extensions, ",")
+}
+
+// ParseTextExtensions decodes text extensions encoded by EncodedTextExtensions.
+func ParseTextExtensions(encoded string) []string {
+	if encoded == "" {
+		return nil
+	}
+	return strings.Split(encoded, ",")
+}
+
+// HasTextExtension reports whether the extension of name is one of extensions. As with BlobTypeForName,
+// extensions are matched case-insensitively, and can be given with or without their leading dot.
+func HasTextExtension(extensions []string, name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == "" {
+		return false
+	}
+	for _, textExt := range extensions {
+		if normalizeExtension(textExt) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// S3's limits on object tags
+const (
+	MaxS3TagCount       = 10
+	MaxS3TagKeyLength   = 128
+	MaxS3TagValueLength = 256
+)
+
+// MaxBlobTagsBytes is the room the plan files have for the URL encoded blob tags.
+const MaxBlobTagsBytes = 4000
+
+// EncodedTags returns the explicit tags URL encoded, as they're stored in the plan files.
+// Tags are encoded from the map when it's given, else BlobTagsString is taken as is.
+func (a BlobTransferAttributes) EncodedTags() string {
+	if len(a.Tags) == 0 {
+		return a.BlobTagsString
+	}
+	tags := url.Values{}
+	for key, value := range a.Tags {
+		tags.Set(key, value)
+	}
+	return tags.Encode()
+}
+
+// validateS3Tags checks tags against S3's limits on object tags. Lengths are counted in Unicode characters.
+func validateS3Tags(tags map[string]string) error {
+	if len(tags) > MaxS3TagCount {
+		return fmt.Errorf("at most %d tags can be set, got %d", MaxS3TagCount, len(tags))
+	}
+	for key, value := range tags {
+		if key == "" {
+			return errors.New("tag keys cannot be empty")
+		}
+		if n := utf8.RuneCountInString(key); n > MaxS3TagKeyLength {
+			return fmt.Errorf("the tag key %q is %d characters long, over the limit of %d", key, n, MaxS3TagKeyLength)
+		}
+		if n := utf8.RuneCountInString(value); n > MaxS3TagValueLength {
+			return fmt.Errorf("the value of tag %q is %d characters long, over the limit of %d", key, n, MaxS3TagValueLength)
+		}
+	}
+	return nil
+}
+
+// SingleShotThreshold returns the size below which files are uploaded in a single request, rather than in blocks.
+// SingleShotThresholdBytes takes precedence over PutBlobSizeInBytes; 0 leaves the engine to default it to the block size.
+func (a BlobTransferAttributes) SingleShotThreshold() int64 {
+	if a.SingleShotThresholdBytes != 0 {
+		return a.SingleShotThresholdBytes
+	}
+	return a.PutBlobSizeInBytes
+}
+
+// UploadCompression returns the algorithm uploads are compressed with, or None if they aren't.
+func (a BlobTransferAttributes) UploadCompression() CompressionAlgorithm {
+	if !a.CompressOnUpload {
+		return ECompressionAlgorithm.None()
+	}
+	if a.CompressionAlgorithm == ECompressionAlgorithm.None() {
+		return ECompressionAlgorithm.Gzip()
+	}
+	return a.CompressionAlgorithm
+}
+
+// ResolveContentDisposition returns the content disposition a destination gets, given its source's.
+func (a BlobTransferAttributes) ResolveContentDisposition(source string) string {
+	return ResolveContentDisposition(a.ContentDisposition, a.PreserveContentDisposition, source)
+}
+
+// ResolveContentDisposition picks a destination's content disposition. An explicit value always wins,
+// else the source's is kept when preserving and stripped when not.
+func ResolveContentDisposition(explicit string, preserve bool, source string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if preserve {
+		return source
+	}
+	return ""
 }
 
 // This struct represents the optional attribute for file request header
@@ -210,6 +1294,9 @@ type JobIDDetails struct {
 type ListJobsResponse struct {
 	ErrorMessage string
 	JobIDDetails []JobIDDetails
+	// JobSummaries holds a lightweight summary of each of the listed jobs, in the same order.
+	// It's only filled in when asked for, since it takes reading every transfer of every job.
+	JobSummaries []ListJobSummaryResponse
 }
 
 // ListContainerResponse represents the list of blobs within the container.
@@ -255,6 +1342,12 @@ type ListJobSummaryResponse struct {
 	// sum of total bytes expected in the job (i.e. based on our current expectation of which files will be successful)
 	TotalBytesExpected uint64 `json:",string"`
 
+	// BytesCompleted, BytesFailed and BytesSkipped split the source sizes of the finished transfers by their outcome.
+	// With the bytes of the transfers yet to finish, they add up to TotalBytesEnumerated.
+	BytesCompleted int64 `json:",string"`
+	BytesFailed    int64 `json:",string"`
+	BytesSkipped   int64 `json:",string"`
+
 	PercentComplete float32 `json:",string"`
 
 	// Stats measured from the network pipeline
@@ -275,6 +1368,179 @@ type ListJobSummaryResponse struct {
 	HardlinksConvertedCount uint32 `json:",string"` // Hardlinks converted count is only applicable for NFS transfers
 	SkippedHardlinkCount    uint32 `json:",string"` // Skipped hardlinks count is only applicable for NFS transfers
 	SkippedSpecialFileCount uint32 `json:",string"`
+	// SkippedZeroByteObjectCount is how many empty files were left out while enumerating, for SkipZeroByteObjects
+	SkippedZeroByteObjectCount uint32 `json:",string"`
+	// TotalNumberOfSourceDeletions is how many sources were deleted after being copied, for MoveSource
+	TotalNumberOfSourceDeletions uint32 `json:",string"`
+
+	// SourceEndpoint and DestinationEndpoint identify the service endpoints of the job (scheme and host only).
+	// They never carry SAS tokens or other credentials, and are empty for local resources.
+	SourceEndpoint      string
+	DestinationEndpoint string
+
+	// AbortedOnFirstError is set when the job was cancelled early because FailFast was requested and a transfer failed
+	AbortedOnFirstError bool
+
+	// TruncatedByLimit is set when enumeration stopped at CopyJobPartOrderRequest.MaxTransfers, leaving out some of the source
+	TruncatedByLimit bool
+
+	// ThroughputBytesPerSecond is how fast BytesOverWire grew over the last progress interval. Only the front end
+	// reporting the job's progress measures it, so it's zero elsewhere.
+	ThroughputBytesPerSecond float64 `json:",string"`
+
+	// AverageTransferSizeBytes is TotalBytesExpected / TotalTransfers, and CompletedAverageSizeBytes is the same for completed transfers only.
+	// Useful to tell a job of many small files from one of a few huge files. Both are zero when there are no transfers.
+	AverageTransferSizeBytes  int64 `json:",string"`
+	CompletedAverageSizeBytes int64 `json:",string"`
+}
+
+// SetAverageTransferSizes computes AverageTransferSizeBytes and CompletedAverageSizeBytes from the totals.
+// It must be called before the bytes of in-flight files are added to TotalBytesTransferred.
+func (js *ListJobSummaryResponse) SetAverageTransferSizes() {
+	js.AverageTransferSizeBytes = 0
+	if js.TotalTransfers > 0 {
+		js.AverageTransferSizeBytes = int64(js.TotalBytesExpected / uint64(js.TotalTransfers))
+	}
+	js.CompletedAverageSizeBytes = 0
+	if js.TransfersCompleted > 0 {
+		js.CompletedAverageSizeBytes = int64(js.TotalBytesTransferred / uint64(js.TransfersCompleted))
+	}
+}
+
+// MergeJobSummaries combines the summaries of a job's parts into one for the whole job. Counts and bytes are summed,
+// the network stats (averaged over their windows) take the parts' maximum, and the transfer details are concatenated.
+// The job failed if any part did, and is in progress if any part is; once all are done, their outcomes are combined.
+func MergeJobSummaries(parts []ListJobSummaryResponse) ListJobSummaryResponse {
+	var merged ListJobSummaryResponse
+	statuses := make(map[JobStatus]bool, len(parts))
+	for i, part := range parts {
+		if i == 0 {
+			merged.JobID = part.JobID
+			merged.PerfConstraint = part.PerfConstraint
+			merged.PerfStrings = part.PerfStrings
+			merged.PerformanceAdvice = part.PerformanceAdvice
+			merged.SourceEndpoint = part.SourceEndpoint
+			merged.DestinationEndpoint = part.DestinationEndpoint
+		}
+		if merged.ErrorMsg == "" {
+			merged.ErrorMsg = part.ErrorMsg
+		}
+		if part.Timestamp.After(merged.Timestamp) {
+			merged.Timestamp = part.Timestamp
+		}
+		statuses[part.JobStatus] = true
+
+		merged.ActiveConnections += part.ActiveConnections
+		merged.CompleteJobOrdered = merged.CompleteJobOrdered || part.CompleteJobOrdered
+		merged.IsCleanupJob = merged.IsCleanupJob || part.IsCleanupJob
+		merged.AbortedOnFirstError = merged.AbortedOnFirstError || part.AbortedOnFirstError
+		merged.TruncatedByLimit = merged.TruncatedByLimit || part.TruncatedByLimit
+
+		merged.TotalTransfers += part.TotalTransfers
+		merged.FileTransfers += part.FileTransfers
+		merged.FolderPropertyTransfers += part.FolderPropertyTransfers
+		merged.SymlinkTransfers += part.SymlinkTransfers
+		merged.FoldersCompleted += part.FoldersCompleted
+		merged.TransfersCompleted += part.TransfersCompleted
+		merged.FoldersFailed += part.FoldersFailed
+		merged.TransfersFailed += part.TransfersFailed
+		merged.FoldersSkipped += part.FoldersSkipped
+		merged.TransfersSkipped += part.TransfersSkipped
+		merged.SkippedSymlinkCount += part.SkippedSymlinkCount
+		merged.HardlinksConvertedCount += part.HardlinksConvertedCount
+		merged.SkippedHardlinkCount += part.SkippedHardlinkCount
+		merged.SkippedSpecialFileCount += part.SkippedSpecialFileCount
+		merged.SkippedZeroByteObjectCount += part.SkippedZeroByteObjectCount
+		merged.TotalNumberOfSourceDeletions += part.TotalNumberOfSourceDeletions
+
+		merged.BytesOverWire += part.BytesOverWire
+		merged.TotalBytesTransferred += part.TotalBytesTransferred
+		merged.BytesCompleted += part.BytesCompleted
+		merged.BytesFailed += part.BytesFailed
+		merged.BytesSkipped += part.BytesSkipped
+		merged.TotalBytesEnumerated += part.TotalBytesEnumerated
+		merged.TotalBytesExpected += part.TotalBytesExpected
+
+		merged.AverageIOPS = max(merged.AverageIOPS, part.AverageIOPS)
+		merged.AverageE2EMilliseconds = max(merged.AverageE2EMilliseconds, part.AverageE2EMilliseconds)
+		merged.ServerBusyPercentage = max(merged.ServerBusyPercentage, part.ServerBusyPercentage)
+		merged.NetworkErrorPercentage = max(merged.NetworkErrorPercentage, part.NetworkErrorPercentage)
+
+		merged.FailedTransfers = append(merged.FailedTransfers, part.FailedTransfers...)
+		merged.SkippedTransfers = append(merged.SkippedTransfers, part.SkippedTransfers...)
+	}
+
+	merged.PercentComplete = 100
+	if merged.TotalBytesExpected != 0 {
+		merged.PercentComplete = min(100, 100*float32(merged.TotalBytesTransferred)/float32(merged.TotalBytesExpected))
+	}
+	merged.SetAverageTransferSizes()
+
+	switch {
+	case statuses[EJobStatus.Failed()]:
+		merged.JobStatus = EJobStatus.Failed()
+	case statuses[EJobStatus.InProgress()]:
+		merged.JobStatus = EJobStatus.InProgress()
+	case statuses[EJobStatus.Cancelling()]:
+		merged.JobStatus = EJobStatus.Cancelling()
+	case statuses[EJobStatus.Paused()]:
+		merged.JobStatus = EJobStatus.Paused()
+	case statuses[EJobStatus.Cancelled()]:
+		merged.JobStatus = EJobStatus.Cancelled()
+	default:
+		merged.JobStatus = merged.JobStatus.EnhanceJobStatusInfo(merged.TransfersSkipped > 0, merged.TransfersFailed > 0, merged.TransfersCompleted > 0)
+	}
+	return merged
+}
+
+// MarshalNDJSON writes the summaries it receives to w as newline-delimited JSON, one compact object per line,
+// until summaries is closed. It's meant for streaming a job's progress; JobSummaryNDJSONReader reads it back.
+func MarshalNDJSON(w io.Writer, summaries <-chan ListJobSummaryResponse) error {
+	encoder := json.NewEncoder(w)
+	for summary := range summaries {
+		if err := encoder.Encode(summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderPrometheusMetrics renders the summary's progress in Prometheus' text exposition format, e.g. for a metrics
+// endpoint to be scraped. Every metric is a gauge labelled with the job's ID.
+func RenderPrometheusMetrics(summary ListJobSummaryResponse) string {
+	var b strings.Builder
+	gauge := func(name, help, value string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s{job_id=\"%s\"} %s\n", name, help, name, name, summary.JobID, value)
+	}
+	count := func(n uint64) string { return strconv.FormatUint(n, 10) }
+
+	gauge("azcopy_transfers_total", "Transfers in the job.", count(uint64(summary.TotalTransfers)))
+	gauge("azcopy_transfers_completed", "Transfers that succeeded.", count(uint64(summary.TransfersCompleted)))
+	gauge("azcopy_transfers_failed", "Transfers that failed.", count(uint64(summary.TransfersFailed)))
+	gauge("azcopy_transfers_skipped", "Transfers that were skipped.", count(uint64(summary.TransfersSkipped)))
+	gauge("azcopy_bytes_transferred", "Bytes of the successful and in progress transfers, without retries.", count(summary.TotalBytesTransferred))
+	gauge("azcopy_bytes_expected", "Bytes the job is expected to transfer.", count(summary.TotalBytesExpected))
+	gauge("azcopy_bytes_over_wire", "Bytes sent over the network, retries included.", count(summary.BytesOverWire))
+	gauge("azcopy_throughput_bytes_per_second", "Bytes sent over the network per second, over the last progress interval.",
+		strconv.FormatFloat(summary.ThroughputBytesPerSecond, 'f', -1, 64))
+	gauge("azcopy_percent_complete", "How much of the job is done, in percent.", strconv.FormatFloat(float64(summary.PercentComplete), 'f', -1, 32))
+	return b.String()
+}
+
+// JobSummaryNDJSONReader reads the job summaries written by MarshalNDJSON.
+type JobSummaryNDJSONReader struct {
+	decoder *json.Decoder
+}
+
+func NewJobSummaryNDJSONReader(r io.Reader) *JobSummaryNDJSONReader {
+	return &JobSummaryNDJSONReader{decoder: json.NewDecoder(r)}
+}
+
+// Next returns the stream's next summary, or io.EOF once there are no more.
+func (r *JobSummaryNDJSONReader) Next() (ListJobSummaryResponse, error) {
+	var summary ListJobSummaryResponse
+	err := r.decoder.Decode(&summary)
+	return summary, err
 }
 
 // wraps the standard ListJobSummaryResponse with sync-specific stats
@@ -284,6 +1550,22 @@ type ListSyncJobSummaryResponse struct {
 	DeleteTransfersCompleted uint32 `json:",string"`
 }
 
+// SyncDiffResult is what a sync would do, computed without transferring or deleting anything.
+// Paths are relative to the source (ToCopy) and the destination (ToDelete).
+type SyncDiffResult struct {
+	ToCopy    []string
+	ToDelete  []string
+	Unchanged uint64 `json:",string"`
+}
+
+// ListPrefixesRequest asks for the common prefixes (virtual directories) directly below Prefix in the S3 bucket Source,
+// split on Delimiter, which defaults to "/".
+type ListPrefixesRequest struct {
+	Source    string
+	Prefix    string
+	Delimiter string
+}
+
 type ListJobTransfersRequest struct {
 	JobID    JobID
 	OfStatus TransferStatus
@@ -304,6 +1586,46 @@ type TransferDetail struct {
 	TransferStatus     TransferStatus
 	TransferSize       uint64
 	ErrorCode          int32 `json:",string"`
+
+	// LastHTTPStatus is the HTTP status of the transfer's last failed attempt, e.g. 403 or 503, or 0 when there's none,
+	// as for successful transfers. ErrorCode keeps the first failure's status instead.
+	LastHTTPStatus int32
+
+	// SourceDeleted reports that the transfer's source was deleted once it succeeded, for CopyJobPartOrderRequest.MoveSource.
+	SourceDeleted bool
+}
+
+// TransferErrorCodeRetriesExhausted is the ErrorCode of the transfers that failed for having used up their
+// BlobTransferAttributes.MaxPerTransferRetries. It's negative, so that it can't be taken for an HTTP status.
+const TransferErrorCodeRetriesExhausted int32 = -1
+
+// retryableErrorCodes are the failures worth retrying, as HTTP statuses or as the error codes S3 and Azure Storage return.
+// They're transient: throttling, timeouts and server side errors.
+var retryableErrorCodes = map[string]bool{
+	"408": true, "429": true, "500": true, "502": true, "503": true, "504": true,
+
+	// S3
+	"SlowDown": true, "RequestTimeout": true, "InternalError": true, "ServiceUnavailable": true,
+
+	// Azure Storage
+	"ServerBusy": true, "OperationTimedOut": true,
+}
+
+// IsRetryableErrorCode reports whether a failure with the given code is likely to succeed when retried.
+// The code is either an HTTP status, e.g. "503", or an S3 or Azure Storage error code, e.g. "SlowDown" or "ServerBusy".
+// Unknown codes, like those of client errors such as 400, 403 and 404, aren't retryable.
+func IsRetryableErrorCode(code string) bool {
+	return retryableErrorCodes[strings.TrimSpace(code)]
+}
+
+// IsRetryable reports whether the transfer failed with an error that's likely to go away when it's retried,
+// going by its last failed attempt's HTTP status if it has one, else by its ErrorCode.
+func (d TransferDetail) IsRetryable() bool {
+	status := d.LastHTTPStatus
+	if status == 0 {
+		status = d.ErrorCode
+	}
+	return IsRetryableErrorCode(strconv.Itoa(int(status)))
 }
 
 type CancelPauseResumeResponse struct {
@@ -331,4 +1653,62 @@ type GetJobDetailsResponse struct {
 	Source      string
 	Destination string
 	TrailingDot TrailingDotOption
+
+	// CommandString and InvocationTime record how the job was invoked. The command string is redacted.
+	CommandString  string
+	InvocationTime time.Time
+}
+
+// ExportJobPlanRequest asks for a job's plan in a machine-readable form, e.g. for auditing or reviewing it offline.
+type ExportJobPlanRequest struct {
+	JobID JobID
+}
+
+// JobPlanExport is a job's plan: every transfer of every part, and the order they were planned from, as far as the
+// plan files keep it. The transfers' paths are relative to Meta's roots. Credentials are redacted; see Redacted.
+type JobPlanExport struct {
+	ErrorMsg  string `json:",omitempty"`
+	Transfers []CopyTransfer
+	Meta      CopyJobPartOrderRequest
+}
+
+// Redacted returns a copy of the export that is safe to share: the SAS tokens and other secrets in its URLs and
+// command string are redacted, and the credentials, clients and handlers that can't be serialized are dropped.
+func (e JobPlanExport) Redacted() JobPlanExport {
+	sanitizer := NewAzCopyLogSanitizer()
+	redactResource := func(r ResourceString) ResourceString {
+		r.Value, r.ExtraQuery = sanitizer.SanitizeLogMessage(r.Value), sanitizer.SanitizeLogMessage(r.ExtraQuery)
+		if r.SAS != "" {
+			r.SAS = redactedSecret
+		}
+		return r
+	}
+
+	redacted := e
+	redacted.Transfers = make([]CopyTransfer, len(e.Transfers))
+	for i, transfer := range e.Transfers {
+		transfer.Source, transfer.Destination = sanitizer.SanitizeLogMessage(transfer.Source), sanitizer.SanitizeLogMessage(transfer.Destination)
+		redacted.Transfers[i] = transfer
+	}
+
+	meta := &redacted.Meta
+	meta.SourceRoot, meta.DestinationRoot = redactResource(meta.SourceRoot), redactResource(meta.DestinationRoot)
+	meta.CommandString = RedactCommandString(meta.CommandString)
+	meta.CompletionWebhookURL = sanitizer.SanitizeLogMessage(meta.CompletionWebhookURL)
+	meta.InventoryManifestURL = sanitizer.SanitizeLogMessage(meta.InventoryManifestURL)
+	meta.CredentialInfo.OAuthTokenInfo = OAuthTokenInfo{}
+	meta.SrcServiceClient, meta.DstServiceClient, meta.JobErrorHandler = nil, nil, nil
+	// the transfers are listed once, above
+	meta.Transfers.List = nil
+	return redacted
+}
+
+// AggregateThroughputResponse sums up the transfers of every job running in this process, e.g. for operators
+// watching the process as a whole.
+type AggregateThroughputResponse struct {
+	// TotalBytesPerSecond is the rate bytes went over the wire at since the previous query, or since the engine started
+	TotalBytesPerSecond float64
+	ActiveJobs          uint32
+	// TotalInFlightBytes counts the bytes already transferred of the active jobs' files that are still in progress
+	TotalInFlightBytes int64
 }
@@ -22,6 +22,8 @@ package common
 
 import (
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"strings"
 
 	minio "github.com/minio/minio-go"
@@ -68,6 +70,73 @@ func (oie *ObjectInfoExtension) ContentMD5() []byte {
 	return b
 }
 
+// s3StorageClassTiers maps S3's storage classes to the blob access tiers closest to them in cost and retrieval time.
+var s3StorageClassTiers = map[string]string{
+	"STANDARD":            "Hot",
+	"REDUCED_REDUNDANCY":  "Hot",
+	"INTELLIGENT_TIERING": "Hot",
+	"EXPRESS_ONEZONE":     "Hot",
+	"STANDARD_IA":         "Cool",
+	"ONEZONE_IA":          "Cool",
+	"GLACIER_IR":          "Cold",
+	"GLACIER":             "Archive",
+	"DEEP_ARCHIVE":        "Archive",
+}
+
+// StorageClassToBlobTier returns the blob access tier an S3 storage class maps to, matching the class case-insensitively.
+// ok is false for classes with no counterpart (e.g. OUTPOSTS), whose objects should get the destination's default tier.
+func StorageClassToBlobTier(class string) (tier string, ok bool) {
+	tier, ok = s3StorageClassTiers[strings.ToUpper(class)]
+	return tier, ok
+}
+
+// destNamePlaceholders are the placeholders RenderDestName fills in.
+var destNamePlaceholders = map[string]bool{"bucket": true, "key": true, "keybase": true, "keydir": true, "version": true}
+
+// DestNameVars returns the variables RenderDestName fills a template in with for an object: its bucket, key and version,
+// the key's last segment as keybase, and the segments before it as keydir, without a trailing '/'.
+func DestNameVars(bucket, key, version string) map[string]string {
+	dirEnd := strings.LastIndex(key, "/")
+	return map[string]string{
+		"bucket":  bucket,
+		"key":     key,
+		"keybase": key[dirEnd+1:],
+		"keydir":  key[:max(dirEnd, 0)],
+		"version": version,
+	}
+}
+
+// RenderDestName fills in the placeholders of a destination naming template, e.g. "{bucket}/archive/{key}",
+// with vars, as returned by DestNameVars. The placeholders are {bucket}, {key}, {keybase}, {keydir} and {version};
+// those vars has no value for are left empty. Unknown placeholders, and braces that don't enclose one, are errors.
+func RenderDestName(template string, vars map[string]string) (string, error) {
+	var name strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start == -1 {
+			start = len(template)
+		}
+		if strings.Contains(template[:start], "}") {
+			return "", errors.New("the destination name template has a '}' that closes no placeholder")
+		}
+		name.WriteString(template[:start])
+		if start == len(template) {
+			return name.String(), nil
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end == -1 {
+			return "", errors.New("the destination name template has a '{' that is never closed")
+		}
+		placeholder := template[start+1 : start+end]
+		if !destNamePlaceholders[placeholder] {
+			return "", fmt.Errorf("the destination name template has the unknown placeholder {%s}", placeholder)
+		}
+		name.WriteString(vars[placeholder])
+		template = template[start+end+1:]
+	}
+}
+
 const s3MetadataPrefix = "x-amz-meta-"
 
 const s3MetadataPrefixLen = len(s3MetadataPrefix)
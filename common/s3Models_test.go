@@ -0,0 +1,65 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageClassToBlobTier(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := map[string]string{
+		"STANDARD":            "Hot",
+		"standard":            "Hot",
+		"INTELLIGENT_TIERING": "Hot",
+		"STANDARD_IA":         "Cool",
+		"ONEZONE_IA":          "Cool",
+		"GLACIER_IR":          "Cold",
+		"GLACIER":             "Archive",
+		"Deep_Archive":        "Archive",
+	}
+	for class, expected := range testCases {
+		tier, ok := StorageClassToBlobTier(class)
+		a.True(ok, class)
+		a.Equal(expected, tier, class)
+	}
+
+	// classes without a counterpart are left to the destination's default
+	for _, class := range []string{"OUTPOSTS", "SNOW", ""} {
+		tier, ok := StorageClassToBlobTier(class)
+		a.False(ok, class)
+		a.Empty(tier, class)
+	}
+}
+
+func TestRenderDestName(t *testing.T) {
+	a := assert.New(t)
+	vars := DestNameVars("bucket", "2024/logs/app.log", "v1")
+	a.Equal("2024/logs", vars["keydir"])
+	a.Equal("app.log", vars["keybase"])
+
+	for template, expected := range map[string]string{
+		"{bucket}/{key}":                      "bucket/2024/logs/app.log",
+		"archive/{bucket}/{keydir}/{keybase}": "archive/bucket/2024/logs/app.log",
+		"{keybase}.{version}":                 "app.log.v1",
+		"flat/{keybase}":                      "flat/app.log",
+		"no placeholders":                     "no placeholders",
+	} {
+		name, err := RenderDestName(template, vars)
+		a.NoError(err, template)
+		a.Equal(expected, name, template)
+	}
+
+	// keys at the bucket's root have no directory, and objects without versions leave theirs empty
+	name, err := RenderDestName("{keydir}{keybase}-{version}", DestNameVars("bucket", "root.txt", ""))
+	a.NoError(err)
+	a.Equal("root.txt-", name)
+
+	_, err = RenderDestName("{bucket}/{year}/{key}", vars)
+	a.ErrorContains(err, "{year}")
+	for _, template := range []string{"{bucket", "bucket}", "{}", "{{key}}"} {
+		_, err = RenderDestName(template, vars)
+		a.Error(err, template)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright © 2025 Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package common
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go"
+)
+
+// s3TaggingPresignExpires is how long the URLs GetS3ObjectTags presigns are valid; they're used right away.
+const s3TaggingPresignExpires = 15 * time.Minute
+
+// s3Tagging is the body of S3's GetObjectTagging response
+type s3Tagging struct {
+	TagSet []struct {
+		Key   string
+		Value string
+	} `xml:"TagSet>Tag"`
+}
+
+// GetS3ObjectTags reads the tags of the object parts points at, with GetObjectTagging. It's a request per object.
+// The S3 client in use predates object tagging, so the request is made against a presigned URL, or, for anonymous
+// access to public buckets, against the object's own URL. It returns nil for objects without tags.
+func GetS3ObjectTags(ctx context.Context, client *minio.Client, parts S3URLParts, requesterPays, anonymous bool) (BlobTags, error) {
+	reqParams := url.Values{}
+	reqParams.Set("tagging", "")
+	if requesterPays {
+		reqParams.Set("x-amz-request-payer", "requester")
+	}
+
+	var tagsURL *url.URL
+	if anonymous {
+		u := parts.URL()
+		u.RawQuery = reqParams.Encode()
+		tagsURL = &u
+	} else {
+		var err error
+		tagsURL, err = client.Presign(http.MethodGet, parts.BucketName, parts.ObjectKey, s3TaggingPresignExpires, reqParams)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tagsURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the object's tags: %s", resp.Status)
+	}
+
+	var tagging s3Tagging
+	if err = xml.NewDecoder(resp.Body).Decode(&tagging); err != nil {
+		return nil, fmt.Errorf("failed to read the object's tags: %w", err)
+	}
+	if len(tagging.TagSet) == 0 {
+		return nil, nil
+	}
+	tags := BlobTags{}
+	for _, tag := range tagging.TagSet {
+		tags[tag.Key] = tag.Value
+	}
+	return tags, nil
+}
@@ -22,9 +22,13 @@ package common
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // S3URLParts represents the components that make up AWS S3 Service/Bucket/Object URL.
@@ -50,20 +54,88 @@ type S3URLParts struct {
 	Region         string // Ex: endpoint region, e.g. "eu-west-1"
 	UnparsedParams string
 
+	// PartNumber is the part of a multipart object the URL refers to through its partNumber query parameter, or 0 if it doesn't
+	PartNumber int
+
+	// UploadID is the multipart upload the URL refers to through its uploadId query parameter, e.g. to resume or abort it
+	UploadID string
+
+	// ResponseContentTypeOverride is the MIME type the URL's response-content-type query parameter makes S3 return
+	// the object with, as presigned download URLs often ask for, or "" if there's none
+	ResponseContentTypeOverride string
+
+	// IsExpressZone is set for S3 Express One Zone's zonal endpoints, e.g. bucket--usw2-az1--x-s3.s3express-usw2-az1.us-west-2.amazonaws.com,
+	// whose AvailabilityZone is then the zone's ID, e.g. "usw2-az1"
+	IsExpressZone    bool
+	AvailabilityZone string
+
+	// IsObjectLambda is set for S3 Object Lambda access points, e.g. mybanner-123456789012.s3-object-lambda.us-east-1.amazonaws.com.
+	// They're addressed like buckets, so BucketName is the whole "<access point>-<account ID>" label; AccessPointName and
+	// AccountID are its two parts.
+	IsObjectLambda  bool
+	AccessPointName string
+	AccountID       string
+
+	// Tenant is the tenant a Ceph RADOS Gateway bucket is qualified by, e.g. "tenant" for the path-style "/tenant:bucket/key",
+	// whose BucketName is then "bucket". It's only parsed with S3URLParseOptions.TenantDelimiter; see QualifiedBucketName.
+	Tenant string
+
 	isPathStyle bool
 	isDualStack bool
+	// pathVersionDelimiter is set when the version was parsed from the path, so that URL puts it back there
+	pathVersionDelimiter string
+	// tenantDelimiter is set when the tenant was parsed from the path, so that URL puts it back there
+	tenantDelimiter string
 	// TODO: Other S3 compatible service which might be with IP endpoint style
 }
 
 const s3HostPattern = "^(?P<bucketName>.+\\.)?s3[.-](?P<dualStackOrRegionOrAWSDomain>[a-z0-9-]+)\\.(?P<regionOrAWSDomainOrCom>[a-z0-9-]+)"
 const invalidS3URLErrorMessage = "Invalid S3 URL. AzCopy supports standard virtual-hosted-style or path-style URLs defined by AWS, E.g: https://bucket.s3.amazonaws.com or https://s3.amazonaws.com/bucket"
 const versionQueryParamKey = "versionId"
+const partNumberQueryParamKey = "partNumber"
+const uploadIDQueryParamKey = "uploadId"
+const responseContentTypeQueryParamKey = "response-content-type"
+const amzDateQueryParamKey = "X-Amz-Date"
+const amzExpiresQueryParamKey = "X-Amz-Expires"
+
+// amzDateFormat is the ISO 8601 basic format of SigV4's X-Amz-Date, e.g. 20240102T150405Z
+const amzDateFormat = "20060102T150405Z"
 const s3KeywordAmazonAWS = "amazonaws"
 const s3KeywordDualStack = "dualstack"
 const s3EssentialHostPart = "amazonaws.com"
 
+// s3BackblazeB2HostPart is the domain of Backblaze B2's S3 compatible endpoints, e.g. s3.us-west-001.backblazeb2.com.
+// Their region tokens carry a numeric zone, which the AWS shaped region regex doesn't match.
+const s3BackblazeB2HostPart = "backblazeb2.com"
+
 var s3HostRegex = regexp.MustCompile(s3HostPattern)
 
+// s3ExpressHostPattern matches S3 Express One Zone's zonal endpoints. Their buckets' names end with "--<zone ID>--x-s3".
+const s3ExpressHostPattern = "^(?P<bucketName>[a-z0-9-]+--x-s3\\.)?s3express-(?P<availabilityZone>[a-z0-9-]+)\\.(?P<region>[a-z0-9-]+)\\.amazonaws\\.com$"
+
+var s3ExpressHostRegex = regexp.MustCompile(s3ExpressHostPattern)
+
+// s3ObjectLambdaHostPattern matches S3 Object Lambda access points' hosts, whose first label is the access point's name
+// followed by the 12 digit ID of the account owning it.
+const s3ObjectLambdaHostPattern = "^(?:(?P<accessPointName>[a-z0-9-]+)-(?P<accountID>[0-9]{12})\\.)?s3-object-lambda\\.(?P<region>[a-z0-9-]+)\\.amazonaws\\.com$"
+
+var s3ObjectLambdaHostRegex = regexp.MustCompile(s3ObjectLambdaHostPattern)
+var s3RegionRegex = regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d$`)
+
+// doubledSchemeRegex matches URLs starting with two schemes, e.g. https://https://bucket.s3.amazonaws.com
+var doubledSchemeRegex = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*://)([a-zA-Z][a-zA-Z0-9+.-]*://)`)
+
+// DoubledSchemeError is returned by NewS3URLPartsFromString for URLs pasted with their scheme twice.
+// url.Parse takes the second scheme's name for the host, so such URLs would otherwise just be reported as not S3 URLs.
+type DoubledSchemeError struct {
+	Prefix string // the doubled schemes, e.g. "https://https://"
+	Scheme string // the scheme the URL is left with once the first is removed, e.g. "https"
+}
+
+func (e *DoubledSchemeError) Error() string {
+	return fmt.Sprintf("the URL starts with the doubled scheme %q, did you mean to start it with %q?", e.Prefix, e.Scheme+"://")
+}
+
 // IsS3URL verifies if a given URL points to S3 URL supported by AzCopy-v10
 func IsS3URL(u url.URL) bool {
 	if _, isS3URL := findS3URLMatches(strings.ToLower(u.Host)); isS3URL {
@@ -73,25 +145,56 @@ func IsS3URL(u url.URL) bool {
 }
 
 func findS3URLMatches(host string) (matches []string, isS3Host bool) {
+	if expressMatches := s3ExpressHostRegex.FindStringSubmatch(host); expressMatches != nil {
+		// Shape them like s3HostRegex's matches: the bucket, then the region, then the AWS domain
+		return []string{expressMatches[0], expressMatches[1], expressMatches[3], s3KeywordAmazonAWS}, true
+	}
 	matchSlices := s3HostRegex.FindStringSubmatch(host) // If match the first element would be entire host, and then follows the sub match strings.
-	if matchSlices == nil || !strings.Contains(host, s3EssentialHostPart) {
+	if matchSlices == nil || !(strings.Contains(host, s3EssentialHostPart) || strings.HasSuffix(host, s3BackblazeB2HostPart)) {
 		return nil, false
 	}
 	return matchSlices, true
 }
 
+// S3URLParseOptions tunes how NewS3URLPartsWithOptions parses URLs. The zero value parses like NewS3URLParts.
+type S3URLParseOptions struct {
+	// PathVersionDelimiter, when set, splits a version encoded in the path by some S3 compatible services off the object key,
+	// e.g. with "@", the key "dir/key@v1" is the object "dir/key" at version "v1". The last occurrence of the delimiter
+	// within the key's last segment is used. A versionId query parameter takes precedence, leaving the path as it is.
+	// URL and String put a version parsed from the path back into the path.
+	PathVersionDelimiter string
+
+	// ForcePathStyle takes the bucket from the first path segment regardless of the host's shape, and the whole host as the endpoint,
+	// i.e. it turns off all virtual-host interpretation, even for hosts that look like bucket.domain.
+	// It's meant for S3 compatible services behind gateways with generic host names, e.g. s3-gw.corp.internal,
+	// so hosts that don't look like S3's are accepted too, as long as there is one. The region is only inferred from hosts that do.
+	ForcePathStyle bool
+
+	// TenantDelimiter, when set, splits a tenant off path-style buckets, as Ceph RADOS Gateway qualifies them,
+	// e.g. with ":", the bucket segment "tenant:bucket" is the bucket "bucket" of the tenant "tenant".
+	// The first occurrence of the delimiter is used, since tenants can't contain it. Buckets without it have no tenant.
+	TenantDelimiter string
+}
+
 // NewS3URLParts parses a URL initializing S3URLParts' fields. This method overwrites all fields in the S3URLParts object.
 func NewS3URLParts(u url.URL) (S3URLParts, error) {
+	return NewS3URLPartsWithOptions(u, S3URLParseOptions{})
+}
+
+// NewS3URLPartsWithOptions parses a URL as NewS3URLParts does, tuned by opts.
+func NewS3URLPartsWithOptions(u url.URL, opts S3URLParseOptions) (S3URLParts, error) {
 	// S3's bucket name should be in lower case
 	host := strings.ToLower(u.Host)
 
 	matchSlices, isS3URL := findS3URLMatches(host)
-	if !isS3URL {
+	if !isS3URL && (!opts.ForcePathStyle || host == "") {
 		return S3URLParts{}, errors.New(invalidS3URLErrorMessage)
 	}
 
-	path := u.Path
-	// Remove the initial '/' if exists
+	// Work on the escaped path, so that an encoded bucket name (e.g. "my%2Bbucket") is split out before it's decoded
+	path := u.EscapedPath()
+	// Remove the initial '/' if exists. Only that one is removed, since S3 allows keys that start with a slash,
+	// e.g. "/bucket//weird/key" is the key "/weird/key" in the bucket "bucket"
 	if path != "" && path[0] == '/' {
 		path = path[1:]
 	}
@@ -102,42 +205,100 @@ func NewS3URLParts(u url.URL) (S3URLParts, error) {
 	}
 
 	// Check what's the path style, and parse accordingly.
-	if matchSlices[1] != "" { // Go's implementation is a bit strange, even if the first subexp fail to be matched, "" will be returned for that sub exp
+	if !opts.ForcePathStyle && matchSlices[1] != "" { // Go's implementation is a bit strange, even if the first subexp fail to be matched, "" will be returned for that sub exp
 		// In this case, it would be in virtual-hosted-style URL, and has host prefix like bucket.s3[-.]
 		up.BucketName = matchSlices[1][:len(matchSlices[1])-1] // Removing the trailing '.' at the end
-		up.ObjectKey = path
+		up.ObjectKey = s3PathUnescape(path)
 
 		up.Endpoint = host[strings.Index(host, ".")+1:]
 	} else {
 		// In this case, it would be in path-style URL. Host prefix like s3[-.], and path contains the bucket name and object id.
 		up.isPathStyle = true
 
+		bucket := path
 		if bucketEndIndex := strings.Index(path, "/"); bucketEndIndex != -1 {
-			up.BucketName = path[:bucketEndIndex]
-			up.ObjectKey = path[bucketEndIndex+1:]
-		} else {
-			up.BucketName = path
+			bucket = path[:bucketEndIndex]
+			up.ObjectKey = s3PathUnescape(path[bucketEndIndex+1:])
 		}
+		if opts.TenantDelimiter != "" {
+			if tenant, tenantBucket, ok := strings.Cut(bucket, opts.TenantDelimiter); ok && tenant != "" && tenantBucket != "" {
+				up.Tenant, bucket = s3PathUnescape(tenant), tenantBucket
+				up.tenantDelimiter = opts.TenantDelimiter
+			}
+		}
+		up.BucketName = s3PathUnescape(bucket)
 
 		up.Endpoint = host
 	}
-	// Check if dualstack is contained in host name
-	if matchSlices[2] == s3KeywordDualStack {
-		up.isDualStack = true
-		if matchSlices[3] != s3KeywordAmazonAWS {
-			up.Region = matchSlices[3]
+	if isS3URL {
+		// Check if dualstack is contained in host name. It may come before or after the region,
+		// e.g. s3.dualstack.eu-west-1.amazonaws.com or s3.eu-west-1.dualstack.amazonaws.com
+		for _, segment := range matchSlices[2:4] {
+			if segment == s3KeywordDualStack {
+				up.isDualStack = true
+			} else if s3RegionRegex.MatchString(segment) {
+				up.Region = segment
+			}
+		}
+		if up.Region == "" {
+			// Not a region-shaped token (e.g. B2's "us-west-001"), so take whatever follows "s3" or "s3.dualstack", unless that's the AWS domain itself
+			regionSegment := matchSlices[2]
+			if regionSegment == s3KeywordDualStack {
+				regionSegment = matchSlices[3]
+			}
+			if regionSegment != s3KeywordAmazonAWS {
+				up.Region = regionSegment
+			}
 		}
-	} else if matchSlices[2] != s3KeywordAmazonAWS {
-		up.Region = matchSlices[2]
+	}
+
+	if expressMatches := s3ExpressHostRegex.FindStringSubmatch(host); expressMatches != nil {
+		up.IsExpressZone = true
+		up.AvailabilityZone = expressMatches[2]
+	}
+
+	if lambdaMatches := s3ObjectLambdaHostRegex.FindStringSubmatch(host); lambdaMatches != nil {
+		up.IsObjectLambda = true
+		up.AccessPointName, up.AccountID = lambdaMatches[1], lambdaMatches[2]
 	}
 
 	// Convert the query parameters to a case-sensitive map & trim whitespace
 	paramsMap := u.Query()
 
-	if versionStr, ok := caseInsensitiveValues(paramsMap).Get(versionQueryParamKey); ok {
+	if key, versionStr, ok := caseInsensitiveValues(paramsMap).Get(versionQueryParamKey); ok {
 		up.Version = versionStr[0]
 		// If we recognized the query parameter, remove it from the map
-		delete(paramsMap, versionQueryParamKey)
+		delete(paramsMap, key)
+	} else if opts.PathVersionDelimiter != "" {
+		// only the key's last segment can carry a version, so that e.g. "user@example.com/key" is left alone
+		lastSegmentIndex := strings.LastIndex(up.ObjectKey, "/") + 1
+		if versionIndex := strings.LastIndex(up.ObjectKey[lastSegmentIndex:], opts.PathVersionDelimiter); versionIndex != -1 {
+			versionIndex += lastSegmentIndex
+			if version := up.ObjectKey[versionIndex+len(opts.PathVersionDelimiter):]; version != "" {
+				up.ObjectKey, up.Version = up.ObjectKey[:versionIndex], version
+				up.pathVersionDelimiter = opts.PathVersionDelimiter
+			}
+		}
+	}
+
+	if key, partNumberStr, ok := caseInsensitiveValues(paramsMap).Get(partNumberQueryParamKey); ok {
+		partNumber, err := strconv.Atoi(partNumberStr[0])
+		if err != nil || partNumber < 1 {
+			return S3URLParts{}, fmt.Errorf("invalid S3 part number %q, it must be a positive integer", partNumberStr[0])
+		}
+		up.PartNumber = partNumber
+		delete(paramsMap, key)
+	}
+
+	if key, uploadIDStr, ok := caseInsensitiveValues(paramsMap).Get(uploadIDQueryParamKey); ok {
+		up.UploadID = uploadIDStr[0]
+		delete(paramsMap, key)
+	}
+
+	// S3's response-* parameters are case-sensitive, so a differently cased one is left unparsed, as S3 would ignore it
+	if contentTypeStr, ok := paramsMap[responseContentTypeQueryParamKey]; ok {
+		up.ResponseContentTypeOverride = contentTypeStr[0]
+		delete(paramsMap, responseContentTypeQueryParamKey)
 	}
 
 	up.UnparsedParams = paramsMap.Encode()
@@ -145,42 +306,391 @@ func NewS3URLParts(u url.URL) (S3URLParts, error) {
 	return up, nil
 }
 
+// minioNonDataPathPrefixes are the paths of MinIO's admin and health APIs, which parse like buckets named "minio".
+var minioNonDataPathPrefixes = []string{"minio/admin", "minio/health"}
+
+// NewS3URLPartsWithWarnings parses a URL as NewS3URLPartsWithOptions does, also returning warnings about URLs that parse
+// but likely aren't what was meant, such as MinIO's admin and health endpoints. See IsMinIONonDataEndpoint.
+func NewS3URLPartsWithWarnings(u url.URL, opts S3URLParseOptions) (S3URLParts, []string, error) {
+	up, err := NewS3URLPartsWithOptions(u, opts)
+	if err != nil {
+		return S3URLParts{}, nil, err
+	}
+
+	var warnings []string
+	if up.IsMinIONonDataEndpoint() {
+		warnings = append(warnings, fmt.Sprintf("%s looks like a MinIO admin or health endpoint, not a bucket; use the URL of the bucket to transfer from instead", up.String()))
+	}
+	return up, warnings, nil
+}
+
+// NewS3URLPartsFromString parses raw as NewS3URLParts does, but also accepts URLs pasted without their scheme,
+// e.g. "bucket.s3.amazonaws.com/key", which url.Parse would take for a path. If raw has no scheme and its host
+// looks like an S3 host, https is assumed. Anything else is parsed as given, so it's held to the same rules as NewS3URLParts.
+// URLs starting with two schemes, e.g. "https://https://bucket.s3.amazonaws.com", are refused with a *DoubledSchemeError.
+func NewS3URLPartsFromString(raw string) (S3URLParts, error) {
+	if match := doubledSchemeRegex.FindStringSubmatch(raw); match != nil {
+		return S3URLParts{}, &DoubledSchemeError{Prefix: match[0], Scheme: strings.TrimSuffix(match[2], "://")}
+	}
+
+	if !strings.Contains(raw, "://") {
+		host := raw
+		if hostEndIndex := strings.IndexAny(host, "/?#"); hostEndIndex != -1 {
+			host = host[:hostEndIndex]
+		}
+		if _, isS3Host := findS3URLMatches(strings.ToLower(host)); isS3Host {
+			raw = "https://" + raw
+		}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return S3URLParts{}, err
+	}
+	return NewS3URLParts(*u)
+}
+
+// ResolveS3Region returns the region requests to the location p parsed from are signed for: the one its URL names,
+// else the explicit one, e.g. an order's SourceRegion. Both may be empty, leaving the client to look the region up.
+func ResolveS3Region(p S3URLParts, explicit string) string {
+	if p.Region != "" {
+		return p.Region
+	}
+	return explicit
+}
+
+// s3EndpointFlavor is the provider an S3 compatible endpoint belongs to, as told by its domain.
+type s3EndpointFlavor string
+
+const (
+	s3FlavorAWS          s3EndpointFlavor = "aws"
+	s3FlavorBackblazeB2  s3EndpointFlavor = "backblazeb2"
+	s3FlavorDigitalOcean s3EndpointFlavor = "digitalocean"
+	s3FlavorWasabi       s3EndpointFlavor = "wasabi"
+)
+
+// s3FlavorDomains tells the flavor of an endpoint by the domain its host ends with.
+var s3FlavorDomains = map[string]s3EndpointFlavor{
+	s3EssentialHostPart:      s3FlavorAWS,
+	s3BackblazeB2HostPart:    s3FlavorBackblazeB2,
+	"digitaloceanspaces.com": s3FlavorDigitalOcean,
+	"wasabisys.com":          s3FlavorWasabi,
+}
+
+// s3FlavorRegionPatterns match the hosts of each flavor's endpoints, with or without a bucket in front, capturing their region.
+// The providers encode it their own way, e.g. AWS's "us-east-2", B2's "us-west-001" or DigitalOcean's "nyc3".
+var s3FlavorRegionPatterns = map[s3EndpointFlavor]*regexp.Regexp{
+	// s3.us-west-2.amazonaws.com, s3-us-west-2.amazonaws.com, s3.dualstack.us-west-2.amazonaws.com, s3express-usw2-az1.us-west-2.amazonaws.com
+	s3FlavorAWS: regexp.MustCompile(`(?:^|[.-])(?P<region>[a-z]{2}(?:-[a-z]+)+-\d)\.(?:dualstack\.)?amazonaws\.com$`),
+	// s3.us-west-001.backblazeb2.com
+	s3FlavorBackblazeB2: regexp.MustCompile(`(?:^|\.)s3\.(?P<region>[a-z]+-[a-z]+-\d{3})\.backblazeb2\.com$`),
+	// nyc3.digitaloceanspaces.com, nyc3.cdn.digitaloceanspaces.com
+	s3FlavorDigitalOcean: regexp.MustCompile(`(?:^|\.)(?P<region>[a-z]+\d)(?:\.cdn)?\.digitaloceanspaces\.com$`),
+	// s3.us-east-2.wasabisys.com
+	s3FlavorWasabi: regexp.MustCompile(`(?:^|\.)s3\.(?P<region>[a-z]{2}(?:-[a-z]+)+-\d)\.wasabisys\.com$`),
+}
+
+// RegionFromEndpoint infers the region an S3 compatible endpoint serves from its host, going by the pattern of the
+// provider its domain belongs to. The endpoint is a host, optionally with a port, or a URL.
+// ok is false for unknown providers, and for endpoints that don't name a region, e.g. AWS's global s3.amazonaws.com.
+func RegionFromEndpoint(endpoint string) (region string, ok bool) {
+	host := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	flavor, ok := s3FlavorOf(host)
+	if !ok {
+		return "", false
+	}
+	pattern := s3FlavorRegionPatterns[flavor]
+	matches := pattern.FindStringSubmatch(host)
+	if matches == nil {
+		return "", false
+	}
+	return matches[pattern.SubexpIndex("region")], true
+}
+
+// s3FlavorOf tells the provider a lower case host belongs to by its domain.
+func s3FlavorOf(host string) (s3EndpointFlavor, bool) {
+	for domain, flavor := range s3FlavorDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return flavor, true
+		}
+	}
+	return "", false
+}
+
+// SameS3Region reports whether a and b are in the same region of the same provider, as server-side copies usually
+// require, e.g. when planning for CopyJobPartOrderRequest.PreferServerSideCopy. Their regions are those their URLs name,
+// else those RegionFromEndpoint infers, with AWS' global endpoint taken for us-east-1. It's false when either is unknown.
+func SameS3Region(a, b S3URLParts) bool {
+	aFlavor, aRegion := a.inferredRegion()
+	bFlavor, bRegion := b.inferredRegion()
+	return aRegion != "" && aFlavor == bFlavor && aRegion == bRegion
+}
+
+// inferredRegion returns the provider of p's endpoint and the region it serves, or "" when either is unknown.
+func (p *S3URLParts) inferredRegion() (s3EndpointFlavor, string) {
+	host := strings.ToLower(p.Host)
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	flavor, ok := s3FlavorOf(host)
+	if !ok {
+		return "", ""
+	}
+	if p.Region != "" {
+		return flavor, p.Region
+	}
+	if region, ok := RegionFromEndpoint(host); ok {
+		return flavor, region
+	}
+	if flavor == s3FlavorAWS {
+		return flavor, s3DefaultRegion
+	}
+	return "", ""
+}
+
+// ParseS3URLList parses a list of S3 URLs separated by newlines, commas or semicolons, e.g. as passed by migration scripts.
+// Whitespace around the entries and empty entries are ignored.
+// The URLs that parsed are returned even when some didn't; the error then joins one error per failed entry,
+// identified by its (0-based) index among the non-empty entries.
+func ParseS3URLList(raw string) ([]S3URLParts, error) {
+	entries := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == '\n' || r == ',' || r == ';'
+	})
+
+	parts := make([]S3URLParts, 0, len(entries))
+	var errs []error
+	index := 0
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		u, err := url.Parse(entry)
+		if err == nil {
+			var p S3URLParts
+			if p, err = NewS3URLParts(*u); err == nil {
+				parts = append(parts, p)
+			}
+		}
+		if err != nil {
+			// don't leak presigned credentials through the error
+			errs = append(errs, fmt.Errorf("entry %d (%s): %w", index, URLStringExtension(entry).RedactSecretQueryParamForLogging(), err))
+		}
+		index++
+	}
+
+	return parts, errors.Join(errs...)
+}
+
+// s3PathUnescape decodes a segment of an escaped URL path.
+// url.Parse has already validated the escapes, so the raw segment is only returned for safety's sake.
+func s3PathUnescape(escaped string) string {
+	if unescaped, err := url.PathUnescape(escaped); err == nil {
+		return unescaped
+	}
+	return escaped
+}
+
 // URL returns a URL object whose fields are initialized from the S3URLParts fields.
 func (p *S3URLParts) URL() url.URL {
-	path := ""
+	path, rawPath := "", ""
 
 	// Concatenate container & blob names (if they exist)
 	if p.BucketName != "" {
 		if p.isPathStyle {
-			path += "/" + p.BucketName
+			path += "/" + p.QualifiedBucketName()
+			// the bucket is encoded as a single segment, so that e.g. an encoded '/' survives the round trip
+			rawPath += "/"
+			if p.Tenant != "" && p.tenantDelimiter != "" {
+				rawPath += url.PathEscape(p.Tenant) + p.tenantDelimiter
+			}
+			rawPath += url.PathEscape(p.BucketName)
 		}
 		if p.ObjectKey != "" {
-			path += "/" + p.ObjectKey
+			objectKey := p.ObjectKey
+			if p.pathVersionDelimiter != "" && p.Version != "" {
+				objectKey += p.pathVersionDelimiter + p.Version
+			}
+			path += "/" + objectKey
+			rawPath += "/" + (&url.URL{Path: objectKey}).EscapedPath()
 		}
 	}
 
 	rawQuery := p.UnparsedParams
 
-	if p.Version != "" {
+	if p.Version != "" && p.pathVersionDelimiter == "" {
 		if len(rawQuery) > 0 {
 			rawQuery += "&"
 		}
 		rawQuery += versionQueryParamKey + "=" + p.Version
 	}
+	if p.PartNumber != 0 {
+		if len(rawQuery) > 0 {
+			rawQuery += "&"
+		}
+		rawQuery += partNumberQueryParamKey + "=" + strconv.Itoa(p.PartNumber)
+	}
+	if p.UploadID != "" {
+		if len(rawQuery) > 0 {
+			rawQuery += "&"
+		}
+		rawQuery += uploadIDQueryParamKey + "=" + url.QueryEscape(p.UploadID)
+	}
+	if p.ResponseContentTypeOverride != "" {
+		if len(rawQuery) > 0 {
+			rawQuery += "&"
+		}
+		rawQuery += responseContentTypeQueryParamKey + "=" + url.QueryEscape(p.ResponseContentTypeOverride)
+	}
 	u := url.URL{
 		Scheme:   p.Scheme,
 		Host:     p.Host,
 		Path:     path,
+		RawPath:  rawPath,
 		RawQuery: rawQuery,
 	}
 	return u
 }
 
+// String returns the URL with any credentials in its query (e.g. a presigned URL's signature) redacted,
+// so that it's safe to log. Use UnmaskedString where the real URL is needed, i.e. to send requests to it.
 func (p *S3URLParts) String() string {
+	return URLExtension{p.URL()}.RedactSecretQueryParamForLogging()
+}
+
+// UnmaskedString returns the URL as is, credentials included.
+func (p *S3URLParts) UnmaskedString() string {
 	u := p.URL()
 	return u.String()
 }
 
+// ObjectURLNoQuery returns the URL of the object p names, without its version or any other query parameters,
+// e.g. to identify the object whichever of its versions is transferred. Nothing secret is left to redact.
+func (p *S3URLParts) ObjectURLNoQuery() string {
+	object := *p
+	object.clearObjectScope()
+	object.UnparsedParams, object.ResponseContentTypeOverride = "", ""
+	u := object.URL()
+	return u.String()
+}
+
+// IsMinIONonDataEndpoint reports whether p's path starts with /minio/admin or /minio/health, the paths of MinIO's admin
+// and health APIs. They're easily pasted by mistake, and would otherwise be taken for the bucket "minio".
+func (p *S3URLParts) IsMinIONonDataEndpoint() bool {
+	path := p.ObjectKey
+	if p.isPathStyle {
+		path = p.BucketName + "/" + p.ObjectKey
+	}
+	for _, prefix := range minioNonDataPathPrefixes {
+		if rest, ok := strings.CutPrefix(path, prefix); ok && (rest == "" || rest[0] == '/') {
+			return true
+		}
+	}
+	return false
+}
+
+// QualifiedBucketName returns the bucket's name qualified by its tenant, e.g. "tenant:bucket", as Ceph RADOS Gateway
+// expects it in requests, or just BucketName if the bucket has no tenant.
+func (p *S3URLParts) QualifiedBucketName() string {
+	if p.Tenant != "" && p.tenantDelimiter != "" {
+		return p.Tenant + p.tenantDelimiter + p.BucketName
+	}
+	return p.BucketName
+}
+
+// s3DefaultRegion is the region requests to AWS' global endpoint are signed for.
+const s3DefaultRegion = "us-east-1"
+
+// SigningEndpoint returns the endpoint and region to sign requests (with SigV4) for.
+// AWS' global endpoint is signed for us-east-1. Services other than AWS get an empty region,
+// so that the caller falls back to the region it's configured with.
+func (p *S3URLParts) SigningEndpoint() (endpoint, region string) {
+	if !strings.Contains(p.Host, s3EssentialHostPart) {
+		return p.Endpoint, ""
+	}
+	if p.Region == "" {
+		return p.Endpoint, s3DefaultRegion
+	}
+	return p.Endpoint, p.Region
+}
+
+// IsPathStyle reports whether the parsed URL named the bucket in its path, e.g. https://s3.amazonaws.com/bucket/key.
+// Service URLs, which have no bucket, are parsed as path-style too. URL keeps the style it was parsed with.
+func (p *S3URLParts) IsPathStyle() bool {
+	return p.isPathStyle
+}
+
+// IsVirtualHosted reports whether the parsed URL named the bucket in its host, e.g. https://bucket.s3.amazonaws.com/key.
+func (p *S3URLParts) IsVirtualHosted() bool {
+	return !p.isPathStyle && p.BucketName != ""
+}
+
+// s3DNSBucketRegex matches the bucket names that can be a DNS label, and so the leftmost part of a virtual host.
+var s3DNSBucketRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// RequiresPathStyle reports whether requests to the bucket must be path-style, because a virtual host can't address it:
+// the endpoint is an IP address (v4 or v6) or a single label host such as localhost, which buckets can't be subdomains of,
+// or the bucket's name isn't a valid DNS label, e.g. it has dots, which also break TLS certificates' wildcards.
+func (p *S3URLParts) RequiresPathStyle() bool {
+	host := p.Endpoint
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if net.ParseIP(host) != nil || !strings.Contains(host, ".") {
+		return true
+	}
+	return p.BucketName != "" && !s3DNSBucketRegex.MatchString(p.BucketName)
+}
+
+// CanonicalRequestTarget returns the Host header and the canonical URI SigV4 signs requests to p with. They're those
+// URL sends the request to, so buckets are addressed in the style p was parsed with. The URI's segments are encoded
+// once, as S3 signs them.
+func (p *S3URLParts) CanonicalRequestTarget() (host, canonicalURI string) {
+	host = p.Host
+	key := p.ObjectKey
+	if key != "" && p.pathVersionDelimiter != "" && p.Version != "" {
+		key += p.pathVersionDelimiter + p.Version
+	}
+
+	switch {
+	case p.BucketName == "":
+		canonicalURI = "/"
+	case p.isPathStyle:
+		canonicalURI = "/" + s3URIEncode(p.QualifiedBucketName(), true)
+		if key != "" {
+			canonicalURI += "/" + s3URIEncode(key, false)
+		}
+	default:
+		canonicalURI = "/" + s3URIEncode(key, false)
+	}
+	return host, canonicalURI
+}
+
+// s3URIEncode encodes s as SigV4's UriEncode does: everything but the unreserved characters A-Z, a-z, 0-9, '-', '.',
+// '_' and '~' is percent-encoded, in upper case hex. '/' is only encoded when encodeSlash is set, e.g. in bucket names.
+func s3URIEncode(s string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '.' || c == '_' || c == '~' || (c == '/' && !encodeSlash) {
+			encoded.WriteByte(c)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return encoded.String()
+}
+
 func (p *S3URLParts) IsServiceSyntactically() bool {
 	if p.Host != "" && p.BucketName == "" {
 		return true
@@ -211,13 +721,271 @@ func (p *S3URLParts) IsDirectorySyntactically() bool {
 	return false
 }
 
+// PointsToSingleObject reports whether the URL names exactly one object, rather than a bucket or a prefix.
+// Unlike IsObjectSyntactically, a key ending with the '/' delimiter is a prefix (i.e. a directory), not an object.
+func (p *S3URLParts) PointsToSingleObject() bool {
+	return p.IsObjectSyntactically() && !p.IsDirectorySyntactically()
+}
+
+// EnumerationPrefix returns the prefix that listing the URL's objects should use, i.e. the object key when it ends with
+// the '/' delimiter, as in "https://s3.amazonaws.com/bucket/prefix/". It's "" when the URL names a single object,
+// whose key mustn't be taken for a prefix, and for a bucket, which is listed in full.
+func (p *S3URLParts) EnumerationPrefix() string {
+	if p.IsDirectorySyntactically() {
+		return p.ObjectKey
+	}
+	return ""
+}
+
+// RelativeKey returns the object key relative to prefix, e.g. "dir/sub/file" relative to "dir" is "sub/file",
+// and whether the key is under prefix at all. The prefix is treated as a directory, with or without its trailing '/',
+// so "dir" doesn't match "dirx/file". A key equal to the prefix yields "". Like S3 keys, the comparison is case-sensitive.
+// When the key doesn't match, it's returned unchanged.
+func (p *S3URLParts) RelativeKey(prefix string) (string, bool) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		return p.ObjectKey, true
+	}
+
+	if p.ObjectKey == prefix || p.ObjectKey == prefix+"/" {
+		return "", true
+	}
+	if relativeKey, found := strings.CutPrefix(p.ObjectKey, prefix+"/"); found {
+		return relativeKey, true
+	}
+	return p.ObjectKey, false
+}
+
+// PresignedExpiry returns when the URL stops working, if it's presigned with SigV4, i.e. X-Amz-Date plus X-Amz-Expires
+// seconds, so that callers can warn about (or refuse) a URL that will expire before the transfer can complete.
+// ok is false when the URL isn't presigned, or its date or expiry can't be parsed.
+func (p *S3URLParts) PresignedExpiry() (expiry time.Time, ok bool) {
+	params, err := url.ParseQuery(p.UnparsedParams)
+	if err != nil {
+		return time.Time{}, false
+	}
+	_, dateStr, hasDate := caseInsensitiveValues(params).Get(amzDateQueryParamKey)
+	_, expiresStr, hasExpires := caseInsensitiveValues(params).Get(amzExpiresQueryParamKey)
+	if !hasDate || !hasExpires {
+		return time.Time{}, false
+	}
+
+	signedAt, err := time.Parse(amzDateFormat, dateStr[0])
+	if err != nil {
+		return time.Time{}, false
+	}
+	expiresIn, err := strconv.ParseUint(expiresStr[0], 10, 32)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return signedAt.Add(time.Duration(expiresIn) * time.Second), true
+}
+
+// clearObjectScope drops what only identifies the object p names, i.e. its version, part number and upload ID,
+// so that p can be turned into the parts of another object, or of its directory or bucket.
+func (p *S3URLParts) clearObjectScope() {
+	p.Version, p.PartNumber, p.UploadID, p.pathVersionDelimiter = "", 0, "", ""
+}
+
+// Child returns the parts of the object at relativeKey under p, which is treated as a directory, with or without its
+// trailing '/'; it's the inverse of RelativeKey. The parent's bucket, endpoint and unparsed query parameters are kept,
+// but not what only identifies the parent itself, i.e. its version, part number and upload ID.
+func (p *S3URLParts) Child(relativeKey string) S3URLParts {
+	child := *p
+	child.clearObjectScope()
+
+	relativeKey = strings.TrimPrefix(relativeKey, "/")
+	if parent := strings.TrimSuffix(p.ObjectKey, "/"); parent != "" && relativeKey != "" {
+		child.ObjectKey = parent + "/" + relativeKey
+	} else if relativeKey != "" {
+		child.ObjectKey = relativeKey
+	}
+	return child
+}
+
+// Parent returns the parts of the directory p is in, with the trailing '/' kept, e.g. "dir/" for "dir/sub/" or "dir/file.txt".
+// Top-level objects and the bucket itself have the bucket root for their parent. As with Child, the version, part number
+// and upload ID aren't kept.
+func (p *S3URLParts) Parent() S3URLParts {
+	parent := *p
+	parent.clearObjectScope()
+
+	key := strings.TrimSuffix(p.ObjectKey, "/")
+	parent.ObjectKey = key[:strings.LastIndex(key, "/")+1]
+	return parent
+}
+
+// BucketRoot returns the parts of the root of p's bucket, e.g. to list the bucket or act on it. The endpoint and addressing
+// style are kept; as with Parent, the version, part number and upload ID aren't.
+func (p *S3URLParts) BucketRoot() S3URLParts {
+	root := *p
+	root.clearObjectScope()
+	root.ObjectKey = ""
+	return root
+}
+
+// BucketProbeURL returns the smallest URL addressing p's bucket, e.g. for a HEAD request checking that it exists or
+// finding its region: the scheme and host, followed by the bucket for path-style URLs. There's no key nor query.
+func (p *S3URLParts) BucketProbeURL() url.URL {
+	root := p.BucketRoot()
+	root.UnparsedParams, root.ResponseContentTypeOverride = "", ""
+	return root.URL()
+}
+
+// s3GlobalEndpoint is the endpoint ListingCacheKey gives every AWS bucket, since their names are unique across regions.
+const s3GlobalEndpoint = "s3.amazonaws.com"
+
+// ListingCacheKey returns a key identifying the listings of p's prefix, e.g. to cache them client side.
+// It's made of the endpoint, the bucket and the key up to its last '/', e.g. "s3.amazonaws.com/bucket/dir/" for
+// https://bucket.s3.us-west-2.amazonaws.com/dir/file.txt?versionId=v1. The scheme, the addressing style, the version and
+// the query don't matter, nor do AWS' regional and dual-stack endpoints: an AWS bucket has the same key through any of them.
+// Other services' endpoints are kept as they are, and Object Lambda access points', whose names aren't buckets'.
+func (p *S3URLParts) ListingCacheKey() string {
+	endpoint := p.Endpoint
+	if strings.Contains(p.Host, s3EssentialHostPart) && !p.IsObjectLambda {
+		endpoint = s3GlobalEndpoint
+	}
+	return endpoint + "/" + p.QualifiedBucketName() + "/" + p.ObjectKey[:strings.LastIndex(p.ObjectKey, "/")+1]
+}
+
+// IsEmptyDirMarker reports whether an S3 object is a directory marker, i.e. a zero-byte object whose key ends with '/',
+// as created by the S3 console's "Create folder".
+func IsEmptyDirMarker(key string, size int64) bool {
+	return size == 0 && strings.HasSuffix(key, "/")
+}
+
+// KeyDepth returns how deep key is below prefix, counted in delimiters: 0 for the prefix's immediate children.
+// Leading and trailing delimiters don't count, so "dir/" is as deep as "dir". A key that doesn't start with prefix is measured whole.
+func KeyDepth(key, prefix, delimiter string) int {
+	if delimiter == "" {
+		return 0
+	}
+	key = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(key, prefix), delimiter), delimiter)
+	return strings.Count(key, delimiter)
+}
+
+// ToAzurePath maps the bucket and object key to an Azure container (or filesystem/share) and blob name.
+// The object key is used as the blob name as-is, and the bucket name is converted with ResolveS3BucketNameForAzure,
+// whose error is returned for bucket names that can't be made valid.
+// Unlike the bucket name resolver used for whole-account copies, this doesn't know about the other buckets,
+// so it cannot detect two buckets mapping to the same container.
+func (p *S3URLParts) ToAzurePath() (container, blobName string, err error) {
+	container, err = ResolveS3BucketNameForAzure(p.BucketName)
+	if err != nil {
+		return "", "", err
+	}
+	return container, p.ObjectKey, nil
+}
+
+// NewCopyTransferFromS3 builds the transfer of an S3 object to a destination under destBase,
+// along with the job roots that the transfer's paths are relative to, as the engine expects.
+// The source root is src's bucket, carrying src's query (e.g. its version, or a presigned URL's credentials);
+// the destination root is destBase, whose query (e.g. a SAS) is split off into the root's SAS.
+// Both relative paths are the object key, so the key "dir/file.txt" lands at destBase/dir/file.txt,
+// regardless of whether destBase has a trailing separator. Keys are escaped for remote roots, and their leading
+// slashes are kept. Keys that denote a directory (i.e. end with '/') and bare buckets become folder transfers,
+// without the trailing '/'.
+func NewCopyTransferFromS3(src S3URLParts, destBase string) (transfer CopyTransfer, sourceRoot, destinationRoot ResourceString) {
+	entityType := EEntityType.File()
+	relativeKey := src.ObjectKey
+	if src.IsDirectorySyntactically() || src.IsBucketSyntactically() {
+		entityType = EEntityType.Folder()
+		relativeKey = strings.TrimSuffix(relativeKey, "/")
+	}
+
+	srcURL := src.URL()
+	bucket := src
+	bucket.ObjectKey, bucket.Version, bucket.PartNumber, bucket.UploadID, bucket.UnparsedParams = "", "", 0, "", ""
+	sourceRoot = ResourceString{Value: bucket.UnmaskedString(), ExtraQuery: srcURL.RawQuery}
+
+	destinationRoot = ResourceString{Value: destBase}
+	isRemoteDest := strings.Contains(destBase, "://")
+	if isRemoteDest {
+		if value, query, found := strings.Cut(destBase, "?"); found {
+			destinationRoot = ResourceString{Value: value, SAS: query}
+		}
+	}
+
+	// GenerateFullPath trims a single leading separator, so prefix one to keep a key's own leading slashes
+	relativePath := func(escape bool) string {
+		if relativeKey == "" {
+			return ""
+		}
+		if !escape {
+			return "/" + relativeKey
+		}
+		segments := strings.Split(relativeKey, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		return "/" + strings.Join(segments, "/")
+	}
+
+	transfer = CopyTransfer{
+		Source:      relativePath(true),
+		Destination: relativePath(isRemoteDest),
+		EntityType:  entityType,
+	}
+	return transfer, sourceRoot, destinationRoot
+}
+
+const (
+	azureContainerNameMinLength = 3
+	azureContainerNameMaxLength = 63
+)
+
+// ResolveS3BucketNameForAzure converts a bucket name into one that satisfies Azure's container (or filesystem/share)
+// naming rules, which only allow lower case letters, numbers and single hyphens, must not start or end with a hyphen,
+// and are 3 to 63 characters long:
+// 1. Upper case letters are lowered. (S3 forbids them in new buckets, but legacy buckets and S3 compatible services may have them.)
+// 2. Periods and underscores are replaced with hyphens, e.g. bucket.with_period -> bucket-with-period
+// 3. Consecutive hyphens are replaced with -[numberOfHyphens]-, e.g. bucket----hyphens -> bucket-4-hyphens
+// 4. Leading and trailing hyphens are trimmed.
+// The name is never truncated, since that could map different buckets to the same container;
+// an error is returned instead when the result is too long or too short.
+func ResolveS3BucketNameForAzure(bucketName string) (string, error) {
+	name := strings.ToLower(bucketName)
+	name = strings.NewReplacer(".", "-", "_", "-").Replace(name)
+
+	var builder strings.Builder
+	consecutiveHyphenCount := 0
+	flushHyphens := func() {
+		switch consecutiveHyphenCount {
+		case 0:
+		case 1:
+			builder.WriteString("-")
+		default:
+			builder.WriteString("-" + strconv.Itoa(consecutiveHyphenCount) + "-")
+		}
+		consecutiveHyphenCount = 0
+	}
+	for _, r := range name {
+		if r == '-' {
+			consecutiveHyphenCount++
+			continue
+		}
+		flushHyphens()
+		builder.WriteRune(r)
+	}
+	flushHyphens()
+
+	name = strings.Trim(builder.String(), "-")
+	if len(name) < azureContainerNameMinLength || len(name) > azureContainerNameMaxLength {
+		return "", fmt.Errorf("the bucket name %q resolves to %q, which is not between %d and %d characters long",
+			bucketName, name, azureContainerNameMinLength, azureContainerNameMaxLength)
+	}
+	return name, nil
+}
+
 type caseInsensitiveValues url.Values // map[string][]string
-func (values caseInsensitiveValues) Get(key string) ([]string, bool) {
+
+// Get returns the values of key, however it's cased, along with the key as it's cased in values, e.g. to delete it.
+func (values caseInsensitiveValues) Get(key string) (string, []string, bool) {
 	key = strings.ToLower(key)
 	for k, v := range values {
 		if strings.ToLower(k) == key {
-			return v, true
+			return k, v, true
 		}
 	}
-	return []string{}, false
+	return "", []string{}, false
 }
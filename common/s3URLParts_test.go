@@ -25,6 +25,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestS3URLParse(t *testing.T) {
@@ -201,4 +202,1396 @@ func TestS3URLParseNegative(t *testing.T) {
 	_, err = NewS3URLParts(*u)
 	a.NotNil(err)
 	a.True(strings.Contains(err.Error(), invalidS3URLErrorMessage))
-}
\ No newline at end of file
+}
+
+func TestS3URLParseEncodedBucketName(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := map[string]string{
+		"https://s3.amazonaws.com/my%2Bbucket/key":            "my+bucket",
+		"https://s3.amazonaws.com/my%20bucket/key":            "my bucket",
+		"https://s3.amazonaws.com/b%C3%BCcket/key":            "bücket",
+		"https://s3-eu-west-1.amazonaws.com/my%2Fbucket/key":  "my/bucket",
+		"https://s3-eu-west-1.amazonaws.com/plain-bucket/key": "plain-bucket",
+	}
+
+	for raw, expectedBucket := range testCases {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.Nil(err)
+		a.Equal(expectedBucket, p.BucketName, raw)
+		a.Equal("key", p.ObjectKey, raw)
+
+		// the bucket must be re-encoded on the way out, and parse back to the same name
+		roundTrip := p.URL()
+		p2, err := NewS3URLParts(roundTrip)
+		a.Nil(err)
+		a.Equal(expectedBucket, p2.BucketName, raw)
+		a.Equal("key", p2.ObjectKey, raw)
+	}
+
+	u, _ := url.Parse("https://s3.amazonaws.com/my%20bucket/dir/my%20file")
+	p, err := NewS3URLParts(*u)
+	a.Nil(err)
+	a.Equal("dir/my file", p.ObjectKey)
+	a.Equal("https://s3.amazonaws.com/my%20bucket/dir/my%20file", p.String())
+
+	u, _ = url.Parse("https://s3.amazonaws.com/my%2Fbucket/key")
+	p, err = NewS3URLParts(*u)
+	a.Nil(err)
+	a.Equal("https://s3.amazonaws.com/my%2Fbucket/key", p.String())
+}
+
+func TestS3URLPartsToAzurePath(t *testing.T) {
+	a := assert.New(t)
+
+	u, _ := url.Parse("https://bucket.s3.amazonaws.com/dir/file.txt")
+	p, err := NewS3URLParts(*u)
+	a.Nil(err)
+	container, blobName, err := p.ToAzurePath()
+	a.NoError(err)
+	a.Equal("bucket", container)
+	a.Equal("dir/file.txt", blobName)
+
+	u, _ = url.Parse("https://s3.amazonaws.com/bucket")
+	p, err = NewS3URLParts(*u)
+	a.Nil(err)
+	container, blobName, err = p.ToAzurePath()
+	a.NoError(err)
+	a.Equal("bucket", container)
+	a.Equal("", blobName)
+
+	// bucket names that Azure wouldn't accept as-is
+	testCases := map[string]string{
+		"bucket.with.period":     "bucket-with-period",
+		"bucket_with_underscore": "bucket-with-underscore",
+		"Legacy_Bucket":          "legacy-bucket",
+		"bucket----hyphens":      "bucket-4-hyphens",
+		"bucket._name":           "bucket-2-name",
+		"_bucket_":               "bucket",
+		strings.Repeat("a", 63):  strings.Repeat("a", 63),
+	}
+	for bucketName, expectedContainer := range testCases {
+		p := S3URLParts{BucketName: bucketName, ObjectKey: "key"}
+		container, blobName, err := p.ToAzurePath()
+		a.NoError(err, bucketName)
+		a.Equal(expectedContainer, container, bucketName)
+		a.Equal("key", blobName)
+	}
+
+	// bucket names that can't be made valid are reported rather than truncated or emptied
+	for _, bucketName := range []string{"_", "a_", "--", strings.Repeat("a", 64), strings.Repeat("a.", 40)} {
+		p := S3URLParts{BucketName: bucketName, ObjectKey: "key"}
+		_, _, err := p.ToAzurePath()
+		a.Error(err, bucketName)
+	}
+}
+
+func TestS3URLParseKeyWithLeadingSlash(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := map[string]string{
+		"https://s3.amazonaws.com/bucket//weird/key":           "/weird/key",
+		"https://s3-eu-west-1.amazonaws.com/bucket///two/deep": "//two/deep",
+		"https://bucket.s3.amazonaws.com//weird/key":           "/weird/key",
+		"https://s3.amazonaws.com/bucket//":                    "/",
+	}
+
+	for raw, expectedKey := range testCases {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.Nil(err)
+		a.Equal("bucket", p.BucketName, raw)
+		a.Equal(expectedKey, p.ObjectKey, raw)
+		a.Equal(raw, p.String())
+
+		// the leading slashes must survive the round trip
+		roundTrip := p.URL()
+		p2, err := NewS3URLParts(roundTrip)
+		a.Nil(err)
+		a.Equal("bucket", p2.BucketName, raw)
+		a.Equal(expectedKey, p2.ObjectKey, raw)
+	}
+
+	// an encoded leading slash is the same key
+	u, _ := url.Parse("https://s3.amazonaws.com/bucket/%2Fweird/key")
+	p, err := NewS3URLParts(*u)
+	a.Nil(err)
+	a.Equal("/weird/key", p.ObjectKey)
+}
+
+func TestNewCopyTransferFromS3(t *testing.T) {
+	a := assert.New(t)
+
+	// the full paths the engine derives from the roots and the transfer's relative paths
+	fullPaths := func(transfer CopyTransfer, sourceRoot, destinationRoot ResourceString) (string, string) {
+		source := GenerateFullPathWithQuery(sourceRoot.Value, transfer.Source, sourceRoot.ExtraQuery)
+		destination := GenerateFullPath(destinationRoot.Value, transfer.Destination)
+		if destinationRoot.SAS != "" {
+			destination += "?" + destinationRoot.SAS
+		}
+		return source, destination
+	}
+
+	// file, with and without a trailing slash on the destination
+	u, _ := url.Parse("https://bucket.s3.amazonaws.com/file.txt")
+	p, err := NewS3URLParts(*u)
+	a.Nil(err)
+	for _, destBase := range []string{"https://account.blob.core.windows.net/container", "https://account.blob.core.windows.net/container/"} {
+		transfer, sourceRoot, destinationRoot := NewCopyTransferFromS3(p, destBase)
+		a.Equal("/file.txt", transfer.Source)
+		a.Equal("/file.txt", transfer.Destination)
+		a.Equal("https://bucket.s3.amazonaws.com", sourceRoot.Value)
+		a.Equal(destBase, destinationRoot.Value)
+		a.Equal(EEntityType.File(), transfer.EntityType)
+		source, destination := fullPaths(transfer, sourceRoot, destinationRoot)
+		a.Equal("https://bucket.s3.amazonaws.com/file.txt", source)
+		a.Equal("https://account.blob.core.windows.net/container/file.txt", destination)
+	}
+
+	// nested
+	u, _ = url.Parse("https://s3.eu-west-1.amazonaws.com/bucket/dir/sub/file.txt")
+	p, err = NewS3URLParts(*u)
+	a.Nil(err)
+	transfer, sourceRoot, destinationRoot := NewCopyTransferFromS3(p, "/data/")
+	a.Equal("/dir/sub/file.txt", transfer.Source)
+	a.Equal("/dir/sub/file.txt", transfer.Destination)
+	a.Equal(EEntityType.File(), transfer.EntityType)
+	source, destination := fullPaths(transfer, sourceRoot, destinationRoot)
+	a.Equal("https://s3.eu-west-1.amazonaws.com/bucket/dir/sub/file.txt", source)
+	a.Equal("/data/dir/sub/file.txt", destination)
+
+	// directory
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/dir/sub/")
+	p, err = NewS3URLParts(*u)
+	a.Nil(err)
+	transfer, sourceRoot, destinationRoot = NewCopyTransferFromS3(p, "/data")
+	a.Equal(EEntityType.Folder(), transfer.EntityType)
+	_, destination = fullPaths(transfer, sourceRoot, destinationRoot)
+	a.Equal("/data/dir/sub", destination)
+
+	// bucket
+	u, _ = url.Parse("https://s3.amazonaws.com/bucket")
+	p, err = NewS3URLParts(*u)
+	a.Nil(err)
+	transfer, sourceRoot, destinationRoot = NewCopyTransferFromS3(p, "/data/")
+	a.Equal("", transfer.Source)
+	a.Equal("", transfer.Destination)
+	a.Equal(EEntityType.Folder(), transfer.EntityType)
+	source, destination = fullPaths(transfer, sourceRoot, destinationRoot)
+	a.Equal("https://s3.amazonaws.com/bucket", source)
+	a.Equal("/data", destination)
+
+	// a key's leading slashes are part of its name
+	u, _ = url.Parse("https://s3.amazonaws.com/bucket//weird/key")
+	p, err = NewS3URLParts(*u)
+	a.Nil(err)
+	transfer, sourceRoot, destinationRoot = NewCopyTransferFromS3(p, "/data")
+	source, destination = fullPaths(transfer, sourceRoot, destinationRoot)
+	a.Equal("https://s3.amazonaws.com/bucket//weird/key", source)
+	a.Equal("/data//weird/key", destination)
+}
+
+func TestNewCopyTransferFromS3EscapesKeysAndSplitsQueries(t *testing.T) {
+	a := assert.New(t)
+
+	// keys with characters that mean something in a URL
+	p := S3URLParts{Scheme: "https", Host: "bucket.s3.amazonaws.com", Endpoint: "s3.amazonaws.com", BucketName: "bucket", ObjectKey: "dir/a#b ?c.txt"}
+	transfer, sourceRoot, destinationRoot := NewCopyTransferFromS3(p, "https://account.blob.core.windows.net/container?sv=2021-06-08&sig=secret")
+	a.Equal("/dir/a%23b%20%3Fc.txt", transfer.Source)
+	a.Equal("/dir/a%23b%20%3Fc.txt", transfer.Destination)
+
+	// the SAS is split off the destination root, rather than ending up in the middle of the path
+	a.Equal("https://account.blob.core.windows.net/container", destinationRoot.Value)
+	a.Equal("sv=2021-06-08&sig=secret", destinationRoot.SAS)
+	destURL, err := url.Parse(GenerateFullPath(destinationRoot.Value, transfer.Destination) + "?" + destinationRoot.SAS)
+	a.NoError(err)
+	a.Equal("/container/dir/a#b ?c.txt", destURL.Path)
+	a.Equal("", destURL.Fragment)
+	a.Equal("secret", destURL.Query().Get("sig"))
+
+	srcURL, err := url.Parse(GenerateFullPathWithQuery(sourceRoot.Value, transfer.Source, sourceRoot.ExtraQuery))
+	a.NoError(err)
+	a.Equal("/dir/a#b ?c.txt", srcURL.Path)
+
+	// local destinations take the key as is
+	transfer, _, destinationRoot = NewCopyTransferFromS3(p, "/data")
+	a.Equal("/dir/a#b ?c.txt", transfer.Destination)
+	a.Equal(ResourceString{Value: "/data"}, destinationRoot)
+}
+
+func TestS3URLParseDualStackRegionOrder(t *testing.T) {
+	a := assert.New(t)
+
+	for _, raw := range []string{
+		"https://bucket.s3.dualstack.eu-west-1.amazonaws.com/key",
+		"https://bucket.s3.eu-west-1.dualstack.amazonaws.com/key",
+		"https://s3.dualstack.eu-west-1.amazonaws.com/bucket/key",
+		"https://s3.eu-west-1.dualstack.amazonaws.com/bucket/key",
+	} {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.Nil(err)
+		a.Equal("eu-west-1", p.Region, raw)
+		a.True(p.isDualStack, raw)
+		a.Equal("bucket", p.BucketName, raw)
+		a.Equal("key", p.ObjectKey, raw)
+	}
+
+	// neither dualstack nor a region
+	u, _ := url.Parse("https://bucket.s3.amazonaws.com/key")
+	p, err := NewS3URLParts(*u)
+	a.Nil(err)
+	a.Equal("", p.Region)
+	a.False(p.isDualStack)
+
+	// dualstack without a region
+	u, _ = url.Parse("https://bucket.s3.dualstack.amazonaws.com/key")
+	p, err = NewS3URLParts(*u)
+	a.Nil(err)
+	a.Equal("", p.Region)
+	a.True(p.isDualStack)
+}
+
+func TestParseS3URLList(t *testing.T) {
+	a := assert.New(t)
+
+	// all valid, with every supported separator
+	parts, err := ParseS3URLList("https://bucket1.s3.amazonaws.com/key1,https://s3.amazonaws.com/bucket2 ;\nhttps://bucket3.s3-eu-west-1.amazonaws.com/dir/\n")
+	a.NoError(err)
+	a.Len(parts, 3)
+	a.Equal("bucket1", parts[0].BucketName)
+	a.Equal("key1", parts[0].ObjectKey)
+	a.Equal("bucket2", parts[1].BucketName)
+	a.Equal("bucket3", parts[2].BucketName)
+	a.Equal("eu-west-1", parts[2].Region)
+
+	// some invalid: the valid ones are still returned, and the error names the failed entries by index
+	parts, err = ParseS3URLList("https://bucket1.s3.amazonaws.com/key1\nhttps://account.blob.core.windows.net/container\nhttps://s3.amazonaws.com/bucket2\n%zz")
+	a.Error(err)
+	a.Len(parts, 2)
+	a.Equal("bucket1", parts[0].BucketName)
+	a.Equal("bucket2", parts[1].BucketName)
+	a.Contains(err.Error(), "entry 1 (https://account.blob.core.windows.net/container)")
+	a.Contains(err.Error(), "entry 3")
+	a.NotContains(err.Error(), "entry 0")
+	a.NotContains(err.Error(), "entry 2")
+
+	// empty input
+	for _, raw := range []string{"", "  \n , ;"} {
+		parts, err = ParseS3URLList(raw)
+		a.NoError(err)
+		a.Empty(parts)
+	}
+}
+
+func TestIsEmptyDirMarker(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(IsEmptyDirMarker("dir/", 0))
+	a.True(IsEmptyDirMarker("dir/sub/", 0))
+	a.True(IsEmptyDirMarker("/", 0))
+
+	// a marker must be empty, and must end with a slash
+	a.False(IsEmptyDirMarker("dir/", 12))
+	a.False(IsEmptyDirMarker("dir", 0))
+	a.False(IsEmptyDirMarker("dir/file.txt", 0))
+	a.False(IsEmptyDirMarker("", 0))
+}
+
+func TestS3URLPartsPresignedExpiry(t *testing.T) {
+	a := assert.New(t)
+	presignedExpiry := func(raw string) (time.Time, bool) {
+		u, err := url.Parse(raw)
+		a.NoError(err)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+		return p.PresignedExpiry()
+	}
+
+	expiry, ok := presignedExpiry("https://bucket.s3.amazonaws.com/key?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=AKIA%2F20240102%2Fus-east-1%2Fs3%2Faws4_request" +
+		"&X-Amz-Date=20240102T150405Z&X-Amz-Expires=3600&X-Amz-SignedHeaders=host&X-Amz-Signature=abc")
+	a.True(ok)
+	a.Equal(time.Date(2024, 1, 2, 16, 4, 5, 0, time.UTC), expiry)
+
+	// parameter names are matched regardless of case
+	expiry, ok = presignedExpiry("https://bucket.s3.amazonaws.com/key?x-amz-date=20240102T150405Z&x-amz-expires=60")
+	a.True(ok)
+	a.Equal(time.Date(2024, 1, 2, 15, 5, 5, 0, time.UTC), expiry)
+
+	// not presigned, or not parseable
+	for _, raw := range []string{
+		"https://bucket.s3.amazonaws.com/key",
+		"https://bucket.s3.amazonaws.com/key?versionId=v1",
+		"https://bucket.s3.amazonaws.com/key?X-Amz-Date=20240102T150405Z",
+		"https://bucket.s3.amazonaws.com/key?X-Amz-Expires=3600",
+		"https://bucket.s3.amazonaws.com/key?X-Amz-Date=2024-01-02&X-Amz-Expires=3600",
+		"https://bucket.s3.amazonaws.com/key?X-Amz-Date=20240102T150405Z&X-Amz-Expires=-1",
+	} {
+		_, ok := presignedExpiry(raw)
+		a.False(ok, raw)
+	}
+}
+
+func TestS3URLPartsChild(t *testing.T) {
+	a := assert.New(t)
+
+	// directory prefixes, with or without the trailing slash
+	for _, raw := range []string{"https://bucket.s3.us-west-2.amazonaws.com/dir/sub/", "https://bucket.s3.us-west-2.amazonaws.com/dir/sub"} {
+		u, _ := url.Parse(raw)
+		parent, err := NewS3URLParts(*u)
+		a.NoError(err)
+
+		child := parent.Child("file.txt")
+		a.Equal("bucket", child.BucketName, raw)
+		a.Equal("us-west-2", child.Region, raw)
+		a.Equal("dir/sub/file.txt", child.ObjectKey, raw)
+		a.Equal("https://bucket.s3.us-west-2.amazonaws.com/dir/sub/file.txt", child.UnmaskedString(), raw)
+		a.Equal("dir/sub/deeper/file.txt", parent.Child("/deeper/file.txt").ObjectKey, raw)
+
+		relativeKey, ok := child.RelativeKey(parent.ObjectKey)
+		a.True(ok, raw)
+		a.Equal("file.txt", relativeKey, raw)
+	}
+
+	// bucket-only parents, virtual-hosted and path-style
+	for _, raw := range []string{"https://bucket.s3.amazonaws.com", "https://bucket.s3.amazonaws.com/", "https://s3.amazonaws.com/bucket"} {
+		u, _ := url.Parse(raw)
+		parent, err := NewS3URLParts(*u)
+		a.NoError(err)
+
+		child := parent.Child("dir/file.txt")
+		a.Equal("dir/file.txt", child.ObjectKey, raw)
+		a.True(child.PointsToSingleObject(), raw)
+		a.Equal(strings.TrimSuffix(raw, "/")+"/dir/file.txt", child.UnmaskedString(), raw)
+	}
+
+	// the parent's own version isn't the child's, but other parameters are kept
+	u, _ := url.Parse("https://bucket.s3.amazonaws.com/dir/?versionId=v1&x-id=GetObject")
+	parent, err := NewS3URLParts(*u)
+	a.NoError(err)
+	child := parent.Child("file.txt")
+	a.Equal("", child.Version)
+	a.Equal("https://bucket.s3.amazonaws.com/dir/file.txt?x-id=GetObject", child.UnmaskedString())
+	a.Equal("v1", parent.Version)
+
+	// no relative key leaves the parent's key
+	a.Equal("dir/", parent.Child("").ObjectKey)
+}
+
+func TestS3URLPartsParent(t *testing.T) {
+	a := assert.New(t)
+	parse := func(raw string) S3URLParts {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+		return p
+	}
+
+	// nested objects and directories
+	p := parse("https://bucket.s3.us-west-2.amazonaws.com/dir/sub/file.txt?versionId=v1")
+	parent := p.Parent()
+	a.Equal("dir/sub/", parent.ObjectKey)
+	a.Equal("", parent.Version)
+	a.Equal("https://bucket.s3.us-west-2.amazonaws.com/dir/sub/", parent.UnmaskedString())
+	parent = parent.Parent()
+	a.Equal("dir/", parent.ObjectKey)
+	p = parse("https://s3.amazonaws.com/bucket/dir/sub")
+	a.Equal("dir/", p.Parent().ObjectKey)
+
+	// top-level objects are in the bucket root
+	for _, raw := range []string{"https://bucket.s3.amazonaws.com/file.txt", "https://bucket.s3.amazonaws.com/dir/", "https://s3.amazonaws.com/bucket/file.txt"} {
+		p := parse(raw)
+		parent := p.Parent()
+		a.Equal("bucket", parent.BucketName, raw)
+		a.Equal("", parent.ObjectKey, raw)
+		a.True(parent.IsBucketSyntactically(), raw)
+	}
+
+	// and so is the bucket root itself
+	p = parse("https://bucket.s3.amazonaws.com")
+	parent = p.Parent()
+	a.Equal("bucket", parent.BucketName)
+	a.Equal("", parent.ObjectKey)
+	a.Equal("https://bucket.s3.amazonaws.com", parent.UnmaskedString())
+}
+
+func TestS3URLPartsObjectURLNoQuery(t *testing.T) {
+	a := assert.New(t)
+	parse := func(raw string, opts S3URLParseOptions) S3URLParts {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLPartsWithOptions(*u, opts)
+		a.NoError(err, raw)
+		return p
+	}
+
+	// the version and the other query parameters are dropped
+	p := parse("https://bucket.s3.us-west-2.amazonaws.com/dir/file.txt?versionId=v1&partNumber=2&X-Amz-Signature=secret&custom=1", S3URLParseOptions{})
+	a.Equal("https://bucket.s3.us-west-2.amazonaws.com/dir/file.txt", p.ObjectURLNoQuery())
+	// without touching the parts themselves
+	a.Equal("v1", p.Version)
+	a.Contains(p.UnparsedParams, "custom=1")
+
+	// every version of an object has the same URL
+	other := parse("https://bucket.s3.us-west-2.amazonaws.com/dir/file.txt?versionId=v2", S3URLParseOptions{})
+	a.Equal(p.ObjectURLNoQuery(), other.ObjectURLNoQuery())
+
+	// path-style URLs keep the bucket, and versions in the path are dropped too
+	p = parse("https://s3.amazonaws.com/bucket/dir/key@v1", S3URLParseOptions{PathVersionDelimiter: "@"})
+	a.Equal("https://s3.amazonaws.com/bucket/dir/key", p.ObjectURLNoQuery())
+
+	// keys are escaped as in the full URL
+	p = parse("https://bucket.s3.amazonaws.com/dir/a%20b.txt?versionId=v1", S3URLParseOptions{})
+	a.Equal("https://bucket.s3.amazonaws.com/dir/a%20b.txt", p.ObjectURLNoQuery())
+}
+
+func TestS3URLPartsListingCacheKey(t *testing.T) {
+	a := assert.New(t)
+	key := func(raw string) string {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+		return p.ListingCacheKey()
+	}
+
+	// an AWS bucket has the same key through any endpoint and addressing style
+	for _, raw := range []string{
+		"https://bucket.s3.amazonaws.com/dir/file.txt",
+		"http://bucket.s3.amazonaws.com/dir/other.txt",
+		"https://bucket.s3.us-west-2.amazonaws.com/dir/file.txt?versionId=v1",
+		"https://bucket.s3-us-west-2.amazonaws.com/dir/",
+		"https://bucket.s3.dualstack.us-west-2.amazonaws.com/dir/file.txt",
+		"https://s3.amazonaws.com/bucket/dir/file.txt",
+		"https://s3.us-west-2.amazonaws.com/bucket/dir/file.txt?partNumber=2&x=y",
+		"https://s3.dualstack.us-west-2.amazonaws.com/bucket/dir/file.txt",
+	} {
+		a.Equal("s3.amazonaws.com/bucket/dir/", key(raw), raw)
+	}
+
+	// the prefixes and buckets still tell listings apart
+	a.Equal("s3.amazonaws.com/bucket/", key("https://bucket.s3.amazonaws.com/file.txt"))
+	a.Equal("s3.amazonaws.com/bucket/", key("https://bucket.s3.amazonaws.com"))
+	a.Equal("s3.amazonaws.com/bucket/dir/sub/", key("https://bucket.s3.amazonaws.com/dir/sub/"))
+	a.Equal("s3.amazonaws.com/other/dir/", key("https://other.s3.amazonaws.com/dir/file.txt"))
+
+	// other services keep their endpoints
+	a.Equal("s3.us-west-001.backblazeb2.com/bucket/dir/", key("https://bucket.s3.us-west-001.backblazeb2.com/dir/file.txt"))
+	a.Equal("s3.us-west-001.backblazeb2.com/bucket/dir/", key("https://s3.us-west-001.backblazeb2.com/bucket/dir/file.txt"))
+	a.Equal("s3-object-lambda.us-east-1.amazonaws.com/ap-123456789012/dir/", key("https://ap-123456789012.s3-object-lambda.us-east-1.amazonaws.com/dir/file.txt"))
+}
+
+func TestS3URLPartsBucketRoot(t *testing.T) {
+	a := assert.New(t)
+	parse := func(raw string) S3URLParts {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+		return p
+	}
+
+	// virtual-hosted style
+	p := parse("https://bucket.s3.us-west-2.amazonaws.com/dir/sub/file.txt?versionId=v1")
+	root := p.BucketRoot()
+	a.Equal("bucket", root.BucketName)
+	a.Equal("", root.ObjectKey)
+	a.Equal("", root.Version)
+	a.Equal("us-west-2", root.Region)
+	a.True(root.IsVirtualHosted())
+	a.True(root.IsBucketSyntactically())
+	a.Equal("https://bucket.s3.us-west-2.amazonaws.com", root.UnmaskedString())
+	// p itself is left alone
+	a.Equal("dir/sub/file.txt", p.ObjectKey)
+
+	// path style
+	p = parse("https://s3.eu-west-1.amazonaws.com/bucket/dir/file.txt?versionId=v1&partNumber=2")
+	root = p.BucketRoot()
+	a.Equal("bucket", root.BucketName)
+	a.Equal(0, root.PartNumber)
+	a.True(root.IsPathStyle())
+	a.Equal("s3.eu-west-1.amazonaws.com", root.Endpoint)
+	a.Equal("https://s3.eu-west-1.amazonaws.com/bucket", root.UnmaskedString())
+
+	// the root is its own root
+	again := root.BucketRoot()
+	a.Equal(root.UnmaskedString(), again.UnmaskedString())
+}
+
+func TestS3URLPartsRelativeKey(t *testing.T) {
+	a := assert.New(t)
+	p := S3URLParts{BucketName: "bucket", ObjectKey: "dir/sub/file.txt"}
+
+	// matched, with and without the trailing slash
+	for _, prefix := range []string{"dir", "dir/"} {
+		relativeKey, ok := p.RelativeKey(prefix)
+		a.True(ok, prefix)
+		a.Equal("sub/file.txt", relativeKey, prefix)
+	}
+	relativeKey, ok := p.RelativeKey("dir/sub")
+	a.True(ok)
+	a.Equal("file.txt", relativeKey)
+	relativeKey, ok = p.RelativeKey("")
+	a.True(ok)
+	a.Equal("dir/sub/file.txt", relativeKey)
+
+	// unmatched: a different directory, a partial segment, or a different case
+	for _, prefix := range []string{"other", "di", "dir/su", "Dir", "dir/sub/file.txt/more"} {
+		relativeKey, ok := p.RelativeKey(prefix)
+		a.False(ok, prefix)
+		a.Equal("dir/sub/file.txt", relativeKey, prefix)
+	}
+
+	// exactly equal
+	relativeKey, ok = p.RelativeKey("dir/sub/file.txt")
+	a.True(ok)
+	a.Equal("", relativeKey)
+	dir := S3URLParts{BucketName: "bucket", ObjectKey: "dir/sub/"}
+	relativeKey, ok = dir.RelativeKey("dir/sub")
+	a.True(ok)
+	a.Equal("", relativeKey)
+}
+
+func TestNewS3URLPartsFromStringWithoutScheme(t *testing.T) {
+	a := assert.New(t)
+
+	p, err := NewS3URLPartsFromString("bucket.s3.amazonaws.com/dir/key.txt")
+	a.NoError(err)
+	a.Equal("https", p.Scheme)
+	a.Equal("bucket", p.BucketName)
+	a.Equal("dir/key.txt", p.ObjectKey)
+	a.Equal("https://bucket.s3.amazonaws.com/dir/key.txt", p.String())
+
+	p, err = NewS3URLPartsFromString("s3.eu-west-1.amazonaws.com/bucket/key?versionId=v1")
+	a.NoError(err)
+	a.Equal("bucket", p.BucketName)
+	a.Equal("key", p.ObjectKey)
+	a.Equal("eu-west-1", p.Region)
+	a.Equal("v1", p.Version)
+
+	// an explicit scheme is left alone
+	p, err = NewS3URLPartsFromString("http://bucket.s3.amazonaws.com/key")
+	a.NoError(err)
+	a.Equal("http", p.Scheme)
+
+	// MinIO-looking hosts aren't S3 hosts, with or without a scheme
+	for _, raw := range []string{"minio.example.com:9000/bucket/key", "s3.minio.local/bucket/key", "https://minio.example.com:9000/bucket/key"} {
+		_, err = NewS3URLPartsFromString(raw)
+		a.Error(err, raw)
+	}
+}
+
+func TestNewS3URLPartsFromStringDoubledScheme(t *testing.T) {
+	a := assert.New(t)
+
+	for raw, expected := range map[string]DoubledSchemeError{
+		"https://https://bucket.s3.amazonaws.com/key": {Prefix: "https://https://", Scheme: "https"},
+		"s3://s3://bucket/key":                        {Prefix: "s3://s3://", Scheme: "s3"},
+		"HTTPS://https://bucket.s3.amazonaws.com":     {Prefix: "HTTPS://https://", Scheme: "https"},
+		"https://s3://bucket/key":                     {Prefix: "https://s3://", Scheme: "s3"},
+	} {
+		_, err := NewS3URLPartsFromString(raw)
+		var doubledSchemeErr *DoubledSchemeError
+		if a.ErrorAs(err, &doubledSchemeErr, raw) {
+			a.Equal(expected, *doubledSchemeErr, raw)
+			a.Contains(err.Error(), expected.Prefix, raw)
+		}
+	}
+
+	// a scheme further into the URL isn't doubled
+	p, err := NewS3URLPartsFromString("https://bucket.s3.amazonaws.com/https://key")
+	a.NoError(err)
+	a.Equal("https://key", p.ObjectKey)
+}
+
+func TestResolveS3Region(t *testing.T) {
+	a := assert.New(t)
+
+	// the URL's region wins
+	p, err := NewS3URLPartsFromString("https://bucket.s3.eu-west-1.amazonaws.com/key")
+	a.NoError(err)
+	a.Equal("eu-west-1", ResolveS3Region(p, "us-east-1"))
+
+	// the explicit one is only a fallback for URLs naming none
+	p, err = NewS3URLPartsFromString("https://bucket.s3.amazonaws.com/key")
+	a.NoError(err)
+	a.Equal("", p.Region)
+	a.Equal("us-east-1", ResolveS3Region(p, "us-east-1"))
+	a.Equal("", ResolveS3Region(p, ""))
+
+	u, _ := url.Parse("https://storage.example.com/bucket/key")
+	p, err = NewS3URLPartsWithOptions(*u, S3URLParseOptions{ForcePathStyle: true})
+	a.NoError(err)
+	a.Equal("auto", ResolveS3Region(p, "auto"))
+}
+
+func TestS3URLParsePathVersionDelimiter(t *testing.T) {
+	a := assert.New(t)
+	u, _ := url.Parse("https://s3.amazonaws.com/bucket/dir/key@v1")
+
+	// by default, the version only comes from the query
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("dir/key@v1", p.ObjectKey)
+	a.Equal("", p.Version)
+
+	opts := S3URLParseOptions{PathVersionDelimiter: "@"}
+	p, err = NewS3URLPartsWithOptions(*u, opts)
+	a.NoError(err)
+	a.Equal("bucket", p.BucketName)
+	a.Equal("dir/key", p.ObjectKey)
+	a.Equal("v1", p.Version)
+
+	// the version is written back into the path
+	a.Equal("https://s3.amazonaws.com/bucket/dir/key@v1", p.UnmaskedString())
+
+	// the last delimiter of the last segment splits, and a trailing delimiter carries no version
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/user@example.com/key@v2")
+	p, err = NewS3URLPartsWithOptions(*u, opts)
+	a.NoError(err)
+	a.Equal("user@example.com/key", p.ObjectKey)
+	a.Equal("v2", p.Version)
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/user@example.com/key")
+	p, err = NewS3URLPartsWithOptions(*u, opts)
+	a.NoError(err)
+	a.Equal("user@example.com/key", p.ObjectKey)
+	a.Equal("", p.Version)
+	a.Equal("https://bucket.s3.amazonaws.com/user@example.com/key", p.UnmaskedString())
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key@")
+	p, err = NewS3URLPartsWithOptions(*u, opts)
+	a.NoError(err)
+	a.Equal("key@", p.ObjectKey)
+	a.Equal("", p.Version)
+
+	// the query wins, and the path is left as it is
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key@v3?versionId=v4")
+	p, err = NewS3URLPartsWithOptions(*u, opts)
+	a.NoError(err)
+	a.Equal("key@v3", p.ObjectKey)
+	a.Equal("v4", p.Version)
+	a.Equal("https://bucket.s3.amazonaws.com/key@v3?versionId=v4", p.UnmaskedString())
+}
+
+func TestS3URLParseForcePathStyle(t *testing.T) {
+	a := assert.New(t)
+	opts := S3URLParseOptions{ForcePathStyle: true}
+
+	// a generic gateway host, whose first label looks like a bucket
+	u, _ := url.Parse("https://bucket.looking.host/realbucket/dir/key")
+	_, err := NewS3URLParts(*u)
+	a.Error(err)
+	p, err := NewS3URLPartsWithOptions(*u, opts)
+	a.NoError(err)
+	a.Equal("bucket.looking.host", p.Endpoint)
+	a.Equal("realbucket", p.BucketName)
+	a.Equal("dir/key", p.ObjectKey)
+	a.Equal("", p.Region)
+	a.Equal("https://bucket.looking.host/realbucket/dir/key", p.String())
+
+	u, _ = url.Parse("https://s3-gw.corp.internal:8443/realbucket")
+	p, err = NewS3URLPartsWithOptions(*u, opts)
+	a.NoError(err)
+	a.Equal("s3-gw.corp.internal:8443", p.Endpoint)
+	a.Equal("realbucket", p.BucketName)
+	a.True(p.IsBucketSyntactically())
+
+	// there must still be a host to send requests to
+	for _, raw := range []string{"/realbucket/key", "file:///realbucket/key", "realbucket/key"} {
+		u, _ = url.Parse(raw)
+		_, err = NewS3URLPartsWithOptions(*u, opts)
+		a.Error(err, raw)
+	}
+
+	// a virtual-hosted-style AWS host is taken as the endpoint, but its region still counts
+	u, _ = url.Parse("https://bucket.s3.eu-west-1.amazonaws.com/realbucket/key")
+	p, err = NewS3URLPartsWithOptions(*u, opts)
+	a.NoError(err)
+	a.Equal("bucket.s3.eu-west-1.amazonaws.com", p.Endpoint)
+	a.Equal("realbucket", p.BucketName)
+	a.Equal("key", p.ObjectKey)
+	a.Equal("eu-west-1", p.Region)
+
+	// no part of the host is ever taken for the bucket
+	u, _ = url.Parse("https://a.b.c/realbucket/key")
+	p, err = NewS3URLPartsWithOptions(*u, opts)
+	a.NoError(err)
+	a.Equal("a.b.c", p.Endpoint)
+	a.Equal("realbucket", p.BucketName)
+	a.Equal("key", p.ObjectKey)
+	a.Equal("https://a.b.c/realbucket/key", p.String())
+}
+
+func TestS3URLParseMinIONonDataEndpoints(t *testing.T) {
+	a := assert.New(t)
+	parse := func(raw string) (S3URLParts, []string) {
+		u, _ := url.Parse(raw)
+		p, warnings, err := NewS3URLPartsWithWarnings(*u, S3URLParseOptions{ForcePathStyle: true})
+		a.NoError(err, raw)
+		return p, warnings
+	}
+
+	for _, raw := range []string{
+		"https://minio.corp.internal:9000/minio/admin/v3/info",
+		"https://minio.corp.internal:9000/minio/admin",
+		"https://minio.corp.internal:9000/minio/health/live",
+		"https://minio.corp.internal:9000/minio/health/ready",
+	} {
+		p, warnings := parse(raw)
+		a.True(p.IsMinIONonDataEndpoint(), raw)
+		a.Len(warnings, 1, raw)
+		a.Contains(warnings[0], "MinIO", raw)
+		// the URL still parses, so that callers can decide what to do with it
+		a.Equal("minio", p.BucketName, raw)
+	}
+
+	// data paths aren't flagged, even in a bucket named minio
+	for _, raw := range []string{
+		"https://minio.corp.internal:9000/bucket/dir/key",
+		"https://minio.corp.internal:9000/minio/dir/key",
+		"https://minio.corp.internal:9000/minio/administration/key",
+		"https://minio.corp.internal:9000/bucket/minio/admin",
+	} {
+		p, warnings := parse(raw)
+		a.False(p.IsMinIONonDataEndpoint(), raw)
+		a.Empty(warnings, raw)
+	}
+
+	// nor are URLs that don't parse
+	u, _ := url.Parse("https://example.com/minio/admin/v3/info")
+	_, warnings, err := NewS3URLPartsWithWarnings(*u, S3URLParseOptions{})
+	a.Error(err)
+	a.Empty(warnings)
+}
+
+func TestS3URLParseTenantDelimiter(t *testing.T) {
+	a := assert.New(t)
+	parse := func(raw string, opts S3URLParseOptions) S3URLParts {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLPartsWithOptions(*u, opts)
+		a.NoError(err, raw)
+		return p
+	}
+	ceph := S3URLParseOptions{ForcePathStyle: true, TenantDelimiter: ":"}
+
+	// without the delimiter, the tenant is part of the bucket's name
+	p := parse("https://rgw.corp.internal/tenant:bucket/dir/key", S3URLParseOptions{ForcePathStyle: true})
+	a.Equal("", p.Tenant)
+	a.Equal("tenant:bucket", p.BucketName)
+	a.Equal("tenant:bucket", p.QualifiedBucketName())
+
+	p = parse("https://rgw.corp.internal/tenant:bucket/dir/key", ceph)
+	a.Equal("tenant", p.Tenant)
+	a.Equal("bucket", p.BucketName)
+	a.Equal("dir/key", p.ObjectKey)
+	a.Equal("tenant:bucket", p.QualifiedBucketName())
+	a.Equal("https://rgw.corp.internal/tenant:bucket/dir/key", p.String())
+	root := p.BucketRoot()
+	a.Equal("https://rgw.corp.internal/tenant:bucket", root.String())
+
+	// the first delimiter splits, and the key is left alone
+	p = parse("https://rgw.corp.internal/tenant:bucket:odd/a:b", ceph)
+	a.Equal("tenant", p.Tenant)
+	a.Equal("bucket:odd", p.BucketName)
+	a.Equal("a:b", p.ObjectKey)
+
+	// buckets without a tenant, or with an empty side, aren't split
+	for _, raw := range []string{"https://rgw.corp.internal/bucket/key", "https://rgw.corp.internal/:bucket/key", "https://rgw.corp.internal/tenant:/key"} {
+		p = parse(raw, ceph)
+		a.Equal("", p.Tenant, raw)
+		a.Equal(raw, p.String())
+	}
+
+	// virtual-hosted-style buckets can't be qualified
+	p = parse("https://bucket.s3.amazonaws.com/tenant:key", S3URLParseOptions{TenantDelimiter: ":"})
+	a.Equal("", p.Tenant)
+	a.Equal("bucket", p.BucketName)
+	a.Equal("tenant:key", p.ObjectKey)
+}
+
+func TestS3URLPartsPointsToSingleObject(t *testing.T) {
+	a := assert.New(t)
+
+	for raw, expected := range map[string]bool{
+		"https://bucket.s3.amazonaws.com/dir/key.txt": true,
+		"https://bucket.s3.amazonaws.com/key":         true,
+		"https://bucket.s3.amazonaws.com/dir/":        false,
+		"https://s3.amazonaws.com/bucket/dir/sub/":    false,
+		"https://bucket.s3.amazonaws.com":             false,
+		"https://s3.amazonaws.com/bucket":             false,
+		"https://s3.amazonaws.com":                    false,
+	} {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+		a.Equal(expected, p.PointsToSingleObject(), raw)
+	}
+}
+
+func TestS3URLPartsStringMasksCredentials(t *testing.T) {
+	a := assert.New(t)
+	u, _ := url.Parse("https://bucket.s3.amazonaws.com/key?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=AKIAEXAMPLE%2F20240101%2Fus-east-1%2Fs3%2Faws4_request&X-Amz-Signature=abcdef0123456789")
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+
+	masked := p.String()
+	a.NotContains(masked, "AKIAEXAMPLE")
+	a.NotContains(masked, "abcdef0123456789")
+	a.Contains(masked, "X-Amz-Signature=REDACTED")
+	a.Contains(masked, "X-Amz-Credential=REDACTED")
+	a.Contains(masked, "X-Amz-Algorithm=AWS4-HMAC-SHA256")
+
+	unmasked := p.UnmaskedString()
+	a.Contains(unmasked, "X-Amz-Credential=AKIAEXAMPLE")
+	a.Contains(unmasked, "X-Amz-Signature=abcdef0123456789")
+
+	// without credentials, both are the same
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key?versionId=abc")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal(p.UnmaskedString(), p.String())
+	a.Equal("https://bucket.s3.amazonaws.com/key?versionId=abc", p.String())
+}
+
+func TestPresignedS3SourceKeepsCredentials(t *testing.T) {
+	a := assert.New(t)
+	u, _ := url.Parse("https://bucket.s3.amazonaws.com/dir/key?X-Amz-Credential=AKIAEXAMPLE%2F20240101%2Fus-east-1%2Fs3%2Faws4_request&X-Amz-Signature=abcdef0123456789")
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+
+	// URLs built to send requests to carry the signature
+	g := NewGenericResourceURLParts(*u, ELocation.S3())
+	a.Equal(u.String(), g.String())
+
+	transfer, sourceRoot, _ := NewCopyTransferFromS3(p, "/data")
+	a.Equal("/dir/key", transfer.Source)
+	a.Equal("https://bucket.s3.amazonaws.com", sourceRoot.Value)
+	a.Contains(sourceRoot.ExtraQuery, "X-Amz-Signature=abcdef0123456789")
+	a.Contains(sourceRoot.ExtraQuery, "X-Amz-Credential=AKIAEXAMPLE")
+	source, err := sourceRoot.FullURL()
+	a.NoError(err)
+	a.Contains(source.String(), "X-Amz-Signature=abcdef0123456789")
+}
+
+func TestKeyDepth(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(0, KeyDepth("file.txt", "", "/"))
+	a.Equal(0, KeyDepth("/file.txt", "", "/"))
+	a.Equal(0, KeyDepth("dir/", "", "/"))
+	a.Equal(1, KeyDepth("dir/file.txt", "", "/"))
+	a.Equal(2, KeyDepth("dir/sub/file.txt", "", "/"))
+
+	// the prefix is cut off first, with or without its trailing delimiter
+	a.Equal(0, KeyDepth("dir/sub/file.txt", "dir/sub/", "/"))
+	a.Equal(0, KeyDepth("dir/sub/file.txt", "dir/sub", "/"))
+	a.Equal(1, KeyDepth("dir/sub/file.txt", "dir", "/"))
+	a.Equal(2, KeyDepth("dir/sub/file.txt", "other", "/"))
+
+	// other delimiters
+	a.Equal(2, KeyDepth("a::b::c", "", "::"))
+	a.Equal(0, KeyDepth("a/b/c", "", ""))
+}
+
+func TestS3URLParseBackblazeB2(t *testing.T) {
+	a := assert.New(t)
+
+	// path-style
+	u, _ := url.Parse("https://s3.us-west-001.backblazeb2.com/bucket/dir/key")
+	a.True(IsS3URL(*u))
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("s3.us-west-001.backblazeb2.com", p.Endpoint)
+	a.Equal("bucket", p.BucketName)
+	a.Equal("dir/key", p.ObjectKey)
+	a.Equal("us-west-001", p.Region)
+	a.Equal("https://s3.us-west-001.backblazeb2.com/bucket/dir/key", p.String())
+
+	// virtual-hosted-style
+	u, _ = url.Parse("https://bucket.s3.eu-central-003.backblazeb2.com/dir/key")
+	a.True(IsS3URL(*u))
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("s3.eu-central-003.backblazeb2.com", p.Endpoint)
+	a.Equal("bucket", p.BucketName)
+	a.Equal("dir/key", p.ObjectKey)
+	a.Equal("eu-central-003", p.Region)
+	a.Equal("https://bucket.s3.eu-central-003.backblazeb2.com/dir/key", p.String())
+
+	// only B2's own domain counts
+	u, _ = url.Parse("https://s3.us-west-001.backblazeb2.com.example.org/bucket")
+	a.False(IsS3URL(*u))
+}
+
+func TestS3URLPartsSigningEndpoint(t *testing.T) {
+	a := assert.New(t)
+	testCases := []struct {
+		rawURL   string
+		opts     S3URLParseOptions
+		endpoint string
+		region   string
+	}{
+		// AWS' global endpoint
+		{"https://s3.amazonaws.com/bucket/key", S3URLParseOptions{}, "s3.amazonaws.com", "us-east-1"},
+		{"https://bucket.s3.amazonaws.com/key", S3URLParseOptions{}, "s3.amazonaws.com", "us-east-1"},
+		// regional AWS endpoints
+		{"https://s3.eu-west-1.amazonaws.com/bucket/key", S3URLParseOptions{}, "s3.eu-west-1.amazonaws.com", "eu-west-1"},
+		{"https://bucket.s3-ap-southeast-2.amazonaws.com/key", S3URLParseOptions{}, "s3-ap-southeast-2.amazonaws.com", "ap-southeast-2"},
+		{"https://bucket.s3.dualstack.us-west-2.amazonaws.com/key", S3URLParseOptions{}, "s3.dualstack.us-west-2.amazonaws.com", "us-west-2"},
+		// other services leave the region to the caller
+		{"https://s3.us-west-001.backblazeb2.com/bucket/key", S3URLParseOptions{}, "s3.us-west-001.backblazeb2.com", ""},
+		{"https://s3-gw.corp.internal:8443/bucket/key", S3URLParseOptions{ForcePathStyle: true}, "s3-gw.corp.internal:8443", ""},
+	}
+
+	for _, tc := range testCases {
+		u, _ := url.Parse(tc.rawURL)
+		p, err := NewS3URLPartsWithOptions(*u, tc.opts)
+		a.NoError(err, tc.rawURL)
+		endpoint, region := p.SigningEndpoint()
+		a.Equal(tc.endpoint, endpoint, tc.rawURL)
+		a.Equal(tc.region, region, tc.rawURL)
+	}
+}
+
+func TestS3URLParseObjectLambda(t *testing.T) {
+	a := assert.New(t)
+
+	// the access point hostname AWS documents for Object Lambda
+	u, _ := url.Parse("https://mybanner-123456789012.s3-object-lambda.us-east-1.amazonaws.com/dir/key")
+	a.True(IsS3URL(*u))
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.True(p.IsObjectLambda)
+	a.Equal("mybanner", p.AccessPointName)
+	a.Equal("123456789012", p.AccountID)
+	a.Equal("us-east-1", p.Region)
+	a.Equal("s3-object-lambda.us-east-1.amazonaws.com", p.Endpoint)
+
+	// it's addressed like a bucket
+	a.Equal("mybanner-123456789012", p.BucketName)
+	a.Equal("dir/key", p.ObjectKey)
+	a.True(p.IsVirtualHosted())
+	a.Equal("https://mybanner-123456789012.s3-object-lambda.us-east-1.amazonaws.com/dir/key", p.String())
+	bucket := p.Parent()
+	bucket = bucket.Parent()
+	a.True(bucket.IsBucketSyntactically())
+	a.Equal("mybanner-123456789012", bucket.BucketName)
+
+	// access point names can have dashes of their own
+	u, _ = url.Parse("https://my-banner-ap-123456789012.s3-object-lambda.eu-west-1.amazonaws.com")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.True(p.IsObjectLambda)
+	a.Equal("my-banner-ap", p.AccessPointName)
+	a.Equal("123456789012", p.AccountID)
+	a.Equal("eu-west-1", p.Region)
+
+	// regular buckets aren't Object Lambda access points
+	u, _ = url.Parse("https://mybanner-123456789012.s3.us-east-1.amazonaws.com/key")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.False(p.IsObjectLambda)
+	a.Equal("", p.AccountID)
+}
+
+func TestS3URLParseExpressZone(t *testing.T) {
+	a := assert.New(t)
+
+	// virtual-hosted-style, the only style S3 Express One Zone serves objects with
+	u, _ := url.Parse("https://bucket--usw2-az1--x-s3.s3express-usw2-az1.us-west-2.amazonaws.com/dir/key")
+	a.True(IsS3URL(*u))
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.True(p.IsExpressZone)
+	a.Equal("usw2-az1", p.AvailabilityZone)
+	a.Equal("us-west-2", p.Region)
+	a.Equal("bucket--usw2-az1--x-s3", p.BucketName)
+	a.Equal("dir/key", p.ObjectKey)
+	a.Equal("s3express-usw2-az1.us-west-2.amazonaws.com", p.Endpoint)
+	a.Equal("https://bucket--usw2-az1--x-s3.s3express-usw2-az1.us-west-2.amazonaws.com/dir/key", p.String())
+	endpoint, region := p.SigningEndpoint()
+	a.Equal("s3express-usw2-az1.us-west-2.amazonaws.com", endpoint)
+	a.Equal("us-west-2", region)
+
+	// the zonal endpoint itself
+	u, _ = url.Parse("https://s3express-use1-az4.us-east-1.amazonaws.com/other--use1-az4--x-s3/key")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.True(p.IsExpressZone)
+	a.Equal("use1-az4", p.AvailabilityZone)
+	a.Equal("us-east-1", p.Region)
+	a.Equal("other--use1-az4--x-s3", p.BucketName)
+	a.Equal("key", p.ObjectKey)
+
+	// regular buckets aren't in an express zone
+	u, _ = url.Parse("https://bucket.s3.us-west-2.amazonaws.com/key")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.False(p.IsExpressZone)
+	a.Equal("", p.AvailabilityZone)
+
+	// a bucket without the directory bucket suffix doesn't fit an express host
+	u, _ = url.Parse("https://bucket.s3express-usw2-az1.us-west-2.amazonaws.com/key")
+	a.False(IsS3URL(*u))
+}
+
+func TestS3URLParsePartNumber(t *testing.T) {
+	a := assert.New(t)
+
+	u, _ := url.Parse("https://bucket.s3.amazonaws.com/dir/key?partNumber=3&x-id=GetObject")
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("dir/key", p.ObjectKey)
+	a.Equal(3, p.PartNumber)
+	a.Equal("x-id=GetObject", p.UnparsedParams)
+	a.Equal("https://bucket.s3.amazonaws.com/dir/key?x-id=GetObject&partNumber=3", p.UnmaskedString())
+
+	// alongside a version
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key?versionId=v1&partNumber=10")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("v1", p.Version)
+	a.Equal(10, p.PartNumber)
+	a.Equal("", p.UnparsedParams)
+	a.Equal("https://bucket.s3.amazonaws.com/key?versionId=v1&partNumber=10", p.UnmaskedString())
+
+	// absent
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal(0, p.PartNumber)
+	a.Equal("https://bucket.s3.amazonaws.com/key", p.UnmaskedString())
+
+	// the parameter is matched however it's cased, and only sent once
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key?PartNumber=3&VersionId=v1")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal(3, p.PartNumber)
+	a.Equal("v1", p.Version)
+	a.Equal("", p.UnparsedParams)
+	a.Equal("https://bucket.s3.amazonaws.com/key?versionId=v1&partNumber=3", p.UnmaskedString())
+	rebuilt := p.URL()
+	a.Equal([]string{"3"}, rebuilt.Query()["partNumber"])
+	a.NotContains(rebuilt.RawQuery, "PartNumber")
+
+	for _, partNumber := range []string{"0", "-1", "three", ""} {
+		u, _ = url.Parse("https://bucket.s3.amazonaws.com/key?partNumber=" + partNumber)
+		_, err = NewS3URLParts(*u)
+		a.Error(err, partNumber)
+	}
+}
+
+func TestS3URLParseUploadID(t *testing.T) {
+	a := assert.New(t)
+
+	// upload IDs are opaque and may hold characters that need escaping
+	u, _ := url.Parse("https://bucket.s3.amazonaws.com/dir/key?uploadId=VXBs%2Bb2Fk~ID&partNumber=2&x-id=UploadPart")
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("dir/key", p.ObjectKey)
+	a.Equal("VXBs+b2Fk~ID", p.UploadID)
+	a.Equal(2, p.PartNumber)
+	a.Equal("x-id=UploadPart", p.UnparsedParams)
+	a.Equal("https://bucket.s3.amazonaws.com/dir/key?x-id=UploadPart&partNumber=2&uploadId=VXBs%2Bb2Fk~ID", p.UnmaskedString())
+
+	// round-trip
+	u, _ = url.Parse(p.UnmaskedString())
+	roundTripped, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal(p.UploadID, roundTripped.UploadID)
+	a.Equal(p.UnmaskedString(), roundTripped.UnmaskedString())
+
+	// the parameter is matched however it's cased, and only sent once
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key?UploadID=abc&partNumber=2")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("abc", p.UploadID)
+	a.Equal("", p.UnparsedParams)
+	rebuilt := p.URL()
+	a.Equal([]string{"abc"}, rebuilt.Query()["uploadId"])
+	a.NotContains(rebuilt.RawQuery, "UploadID")
+
+	// absent
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key?versionId=v1")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("", p.UploadID)
+	a.Equal("", p.UnparsedParams)
+	a.Equal("https://bucket.s3.amazonaws.com/key?versionId=v1", p.UnmaskedString())
+}
+
+func TestS3URLParseResponseContentTypeOverride(t *testing.T) {
+	a := assert.New(t)
+
+	// the value is unescaped, and escaped again when the URL is rebuilt
+	u, _ := url.Parse("https://bucket.s3.amazonaws.com/dir/key?response-content-type=text%2Fplain%3B%20charset%3Dutf-8&X-Amz-Expires=60")
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("text/plain; charset=utf-8", p.ResponseContentTypeOverride)
+	a.Equal("X-Amz-Expires=60", p.UnparsedParams)
+	a.Equal("https://bucket.s3.amazonaws.com/dir/key?X-Amz-Expires=60&response-content-type=text%2Fplain%3B+charset%3Dutf-8", p.UnmaskedString())
+
+	// round-trip
+	u, _ = url.Parse(p.UnmaskedString())
+	roundTripped, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal(p.ResponseContentTypeOverride, roundTripped.ResponseContentTypeOverride)
+	a.Equal(p.UnmaskedString(), roundTripped.UnmaskedString())
+
+	// it's not part of the object's identity
+	a.Equal("https://bucket.s3.amazonaws.com/dir/key", p.ObjectURLNoQuery())
+
+	// absent
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key?versionId=v1")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("", p.ResponseContentTypeOverride)
+	a.Equal("https://bucket.s3.amazonaws.com/key?versionId=v1", p.UnmaskedString())
+
+	// S3 ignores a differently cased parameter, so it's left as it is
+	u, _ = url.Parse("https://bucket.s3.amazonaws.com/key?Response-Content-Type=text%2Fplain")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("", p.ResponseContentTypeOverride)
+	a.Equal("Response-Content-Type=text%2Fplain", p.UnparsedParams)
+}
+
+func TestS3URLPartsAddressingStyle(t *testing.T) {
+	a := assert.New(t)
+	parse := func(raw string) S3URLParts {
+		u, err := url.Parse(raw)
+		a.NoError(err)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+		return p
+	}
+
+	for _, raw := range []string{
+		"https://s3.amazonaws.com/bucket/key",
+		"https://s3.eu-west-1.amazonaws.com/bucket",
+		"https://s3.dualstack.us-east-1.amazonaws.com/bucket/dir/",
+		"s3://s3.amazonaws.com/bucket/key",
+	} {
+		p := parse(raw)
+		a.True(p.IsPathStyle(), raw)
+		a.False(p.IsVirtualHosted(), raw)
+	}
+
+	for _, raw := range []string{
+		"https://bucket.s3.amazonaws.com/key",
+		"https://bucket.s3.eu-west-1.amazonaws.com",
+		"https://bucket.s3-eu-west-1.amazonaws.com/dir/",
+		"s3://bucket.s3.amazonaws.com/key",
+	} {
+		p := parse(raw)
+		a.True(p.IsVirtualHosted(), raw)
+		a.False(p.IsPathStyle(), raw)
+	}
+
+	// a service URL names no bucket either way
+	p := parse("https://s3.amazonaws.com")
+	a.False(p.IsVirtualHosted())
+
+	// forcing path-style reads a virtual-host looking URL's path as bucket and key
+	u, _ := url.Parse("https://bucket.s3-gw.corp.internal/other/key")
+	p, err := NewS3URLPartsWithOptions(*u, S3URLParseOptions{ForcePathStyle: true})
+	a.NoError(err)
+	a.True(p.IsPathStyle())
+	a.Equal("other", p.BucketName)
+}
+
+func TestS3URLPartsRequiresPathStyle(t *testing.T) {
+	a := assert.New(t)
+	requiresPathStyle := func(raw string) bool {
+		u, err := url.Parse(raw)
+		a.NoError(err)
+		p, err := NewS3URLPartsWithOptions(*u, S3URLParseOptions{ForcePathStyle: true})
+		a.NoError(err, raw)
+		return p.RequiresPathStyle()
+	}
+
+	// IP literals, with or without ports
+	a.True(requiresPathStyle("http://10.0.0.5/bucket/key"))
+	a.True(requiresPathStyle("http://10.0.0.5:9000/bucket/key"))
+	a.True(requiresPathStyle("http://[::1]:9000/bucket/key"))
+	a.True(requiresPathStyle("http://[fd00::5]/bucket/key"))
+
+	// single label hosts
+	a.True(requiresPathStyle("http://localhost:9000/bucket/key"))
+	a.True(requiresPathStyle("http://minio/bucket/key"))
+
+	// normal FQDNs, unless the bucket can't be a DNS label
+	a.False(requiresPathStyle("https://s3-gw.corp.internal/bucket/key"))
+	a.False(requiresPathStyle("https://s3-gw.corp.internal:8443/bucket/key"))
+	a.True(requiresPathStyle("https://s3-gw.corp.internal/my.bucket/key"))
+	a.True(requiresPathStyle("https://s3-gw.corp.internal/My_Bucket/key"))
+
+	u, _ := url.Parse("https://bucket.s3.us-west-2.amazonaws.com/key")
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.False(p.RequiresPathStyle())
+	u, _ = url.Parse("https://s3.us-west-2.amazonaws.com/my.bucket/key")
+	p, err = NewS3URLParts(*u)
+	a.NoError(err)
+	a.True(p.RequiresPathStyle())
+}
+
+func TestS3URLPartsEnumerationPrefix(t *testing.T) {
+	a := assert.New(t)
+
+	for raw, expected := range map[string]string{
+		// a trailing delimiter asks for everything under the prefix
+		"https://s3.amazonaws.com/bucket/prefix/":     "prefix/",
+		"https://bucket.s3.amazonaws.com/dir/sub/":    "dir/sub/",
+		"https://s3.amazonaws.com/bucket/dir%20name/": "dir name/",
+		// an exact object isn't a prefix of its namesakes, e.g. "prefix" of "prefix2"
+		"https://s3.amazonaws.com/bucket/prefix":      "",
+		"https://bucket.s3.amazonaws.com/dir/key.txt": "",
+		"https://s3.amazonaws.com/bucket":             "",
+		"https://bucket.s3.us-west-2.amazonaws.com":   "",
+	} {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+		a.Equal(expected, p.EnumerationPrefix(), raw)
+	}
+}
+
+func TestRegionFromEndpoint(t *testing.T) {
+	a := assert.New(t)
+
+	for endpoint, expected := range map[string]string{
+		"s3.us-east-2.amazonaws.com":                       "us-east-2",
+		"bucket.s3-us-gov-west-1.amazonaws.com":            "us-gov-west-1",
+		"https://s3.dualstack.eu-west-1.amazonaws.com/key": "eu-west-1",
+		"s3express-usw2-az1.us-west-2.amazonaws.com":       "us-west-2",
+		"s3.us-west-001.backblazeb2.com":                   "us-west-001",
+		"https://bucket.nyc3.digitaloceanspaces.com":       "nyc3",
+		"S3.US-EAST-2.WASABISYS.COM:443":                   "us-east-2",
+	} {
+		region, ok := RegionFromEndpoint(endpoint)
+		a.True(ok, endpoint)
+		a.Equal(expected, region, endpoint)
+	}
+
+	// neither unknown providers nor endpoints without a region tell one
+	for _, endpoint := range []string{"s3.amazonaws.com", "minio.example.com:9000", "storage.googleapis.com", "digitaloceanspaces.com", ""} {
+		region, ok := RegionFromEndpoint(endpoint)
+		a.False(ok, endpoint)
+		a.Empty(region, endpoint)
+	}
+}
+
+func TestSameS3Region(t *testing.T) {
+	a := assert.New(t)
+	parse := func(raw string) S3URLParts {
+		u, err := url.Parse(raw)
+		a.NoError(err, raw)
+		// other providers' endpoints are only parsed path-style
+		p, err := NewS3URLPartsWithOptions(*u, S3URLParseOptions{ForcePathStyle: !IsS3URL(*u)})
+		a.NoError(err, raw)
+		return p
+	}
+
+	// same region, whatever the addressing style
+	a.True(SameS3Region(parse("https://src.s3.us-west-2.amazonaws.com/key"), parse("https://s3.us-west-2.amazonaws.com/dst/key")))
+	a.True(SameS3Region(parse("https://s3.us-west-001.backblazeb2.com/src"), parse("https://dst.s3.us-west-001.backblazeb2.com")))
+	// different regions
+	a.False(SameS3Region(parse("https://src.s3.us-west-2.amazonaws.com"), parse("https://dst.s3.eu-west-1.amazonaws.com")))
+	// nor are the same region names of different providers the same region
+	a.False(SameS3Region(parse("https://src.s3.us-east-2.amazonaws.com"), parse("https://s3.us-east-2.wasabisys.com/dst")))
+
+	// the global endpoint serves us-east-1
+	a.True(SameS3Region(parse("https://src.s3.amazonaws.com"), parse("https://s3.us-east-1.amazonaws.com/dst")))
+	a.False(SameS3Region(parse("https://s3.amazonaws.com/src"), parse("https://dst.s3.us-west-2.amazonaws.com")))
+
+	// unknown regions never match, not even each other
+	a.False(SameS3Region(parse("https://minio.example.com:9000/src"), parse("https://minio.example.com:9000/dst")))
+	a.False(SameS3Region(parse("https://minio.example.com/src"), parse("https://src.s3.us-east-1.amazonaws.com")))
+}
+
+func TestS3URLParseRegionShapedBucket(t *testing.T) {
+	a := assert.New(t)
+
+	for raw, expectedRegion := range map[string]string{
+		"https://s3.amazonaws.com/us-east-1/key":           "",
+		"https://s3.eu-west-1.amazonaws.com/us-east-1/key": "eu-west-1",
+		"https://s3-us-west-2.amazonaws.com/us-east-1/key": "us-west-2",
+		"https://s3.amazonaws.com/eu-central-1":            "",
+	} {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+
+		// the first path segment is the bucket, however much it looks like a region, and the region only comes from the host
+		a.Equal(strings.Split(strings.TrimPrefix(u.Path, "/"), "/")[0], p.BucketName, raw)
+		a.Equal(expectedRegion, p.Region, raw)
+		a.True(p.IsPathStyle(), raw)
+		a.Equal(raw, p.String(), raw)
+	}
+
+	u, _ := url.Parse("https://s3.amazonaws.com/us-east-1/key")
+	p, err := NewS3URLParts(*u)
+	a.NoError(err)
+	a.Equal("key", p.ObjectKey)
+}
+
+func TestS3URLPartsBucketProbeURL(t *testing.T) {
+	a := assert.New(t)
+
+	for raw, expected := range map[string]string{
+		// virtual-hosted URLs name the bucket in their host
+		"https://bucket.s3.us-west-2.amazonaws.com/dir/key.txt?versionId=v1": "https://bucket.s3.us-west-2.amazonaws.com",
+		"https://bucket.s3.amazonaws.com":                                    "https://bucket.s3.amazonaws.com",
+		// path-style URLs in their path
+		"https://s3.us-west-2.amazonaws.com/bucket/dir/key.txt":                          "https://s3.us-west-2.amazonaws.com/bucket",
+		"http://s3.amazonaws.com/bucket/key?X-Amz-Signature=abc&response-content-type=x": "http://s3.amazonaws.com/bucket",
+	} {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+		probe := p.BucketProbeURL()
+		a.Equal(expected, probe.String(), raw)
+	}
+}
+
+func TestS3URLPartsCanonicalRequestTarget(t *testing.T) {
+	a := assert.New(t)
+
+	for raw, expected := range map[string][2]string{
+		// AWS virtual-hosted URLs keep their style
+		"https://bucket.s3.us-west-2.amazonaws.com/dir/a b+c.txt": {"bucket.s3.us-west-2.amazonaws.com", "/dir/a%20b%2Bc.txt"},
+		"https://bucket.s3.amazonaws.com":                         {"bucket.s3.amazonaws.com", "/"},
+		// as do path-style ones, since they're sent that way
+		"https://s3.us-west-2.amazonaws.com/bucket/key=1":  {"s3.us-west-2.amazonaws.com", "/bucket/key%3D1"},
+		"https://s3.us-west-2.amazonaws.com/my.bucket/key": {"s3.us-west-2.amazonaws.com", "/my.bucket/key"},
+		"https://s3.amazonaws.com":                         {"s3.amazonaws.com", "/"},
+	} {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLParts(*u)
+		a.NoError(err, raw)
+		host, canonicalURI := p.CanonicalRequestTarget()
+		a.Equal(expected, [2]string{host, canonicalURI}, raw)
+	}
+
+	// IP endpoints are always path-style
+	for raw, expected := range map[string][2]string{
+		"http://127.0.0.1:9000/bucket/dir/key.txt": {"127.0.0.1:9000", "/bucket/dir/key.txt"},
+		"http://[::1]:9000/bucket":                 {"[::1]:9000", "/bucket"},
+		"http://10.0.0.5/bucket/a:b":               {"10.0.0.5", "/bucket/a%3Ab"},
+	} {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLPartsWithOptions(*u, S3URLParseOptions{ForcePathStyle: true})
+		a.NoError(err, raw)
+		host, canonicalURI := p.CanonicalRequestTarget()
+		a.Equal(expected, [2]string{host, canonicalURI}, raw)
+	}
+
+	// the target is where URL sends the request, whatever the style was forced to
+	for _, raw := range []string{"https://bucket.s3.us-west-2.amazonaws.com/dir/key.txt", "https://minio.example.com/bucket/dir/key.txt"} {
+		u, _ := url.Parse(raw)
+		p, err := NewS3URLPartsWithOptions(*u, S3URLParseOptions{ForcePathStyle: true})
+		a.NoError(err, raw)
+		host, canonicalURI := p.CanonicalRequestTarget()
+		sent := p.URL()
+		a.Equal(sent.Host, host, raw)
+		a.Equal(sent.EscapedPath(), canonicalURI, raw)
+	}
+}
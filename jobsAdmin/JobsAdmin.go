@@ -83,6 +83,10 @@ var JobsAdmin interface {
 	// returns the current value of bytesOverWire.
 	BytesOverWire() int64
 
+	// BytesPerSecondSinceLastSample returns the rate bytes went over the wire at since it was last called,
+	// or since the engine started, and starts the next sample.
+	BytesPerSecondSinceLastSample() float64
+
 	//DeleteJob(jobID common.JobID)
 
 	TryGetPerformanceAdvice(bytesInJob uint64, filesInJob uint32, fromTo common.FromTo, dir common.TransferDirection, p *ste.PipelineNetworkStats) []common.PerformanceAdvice
@@ -130,6 +134,8 @@ func initJobsAdmin(appCtx context.Context, concurrency ste.ConcurrencySettings,
 		appCtx:             appCtx,
 		commandLineMbpsCap: targetRateInMegaBitsPerSec,
 	}
+	// the first throughput sample runs from now
+	ja.throughputSampleTime = time.Now()
 	// create new context with the defaultService api version set as value to serviceAPIVersionOverride in the app context.
 	ja.appCtx = context.WithValue(ja.appCtx, ste.ServiceAPIVersionOverride, ste.DefaultServiceApiVersion)
 
@@ -238,6 +244,11 @@ type jobsAdmin struct {
 	concurrencyTuner   ste.ConcurrencyTuner
 	commandLineMbpsCap float64
 	cpuMonitor         common.CPUMonitor
+
+	// throughputSampleMu guards the last sample BytesPerSecondSinceLastSample measured from
+	throughputSampleMu    sync.Mutex
+	throughputSampleBytes int64
+	throughputSampleTime  time.Time
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -309,6 +320,20 @@ func (ja *jobsAdmin) BytesOverWire() int64 {
 	return ja.pacer.GetTotalTraffic()
 }
 
+func (ja *jobsAdmin) BytesPerSecondSinceLastSample() float64 {
+	ja.throughputSampleMu.Lock()
+	defer ja.throughputSampleMu.Unlock()
+
+	bytes, now := ja.BytesOverWire(), time.Now()
+	elapsedSeconds := now.Sub(ja.throughputSampleTime).Seconds()
+	bytesPerSecond := float64(0)
+	if elapsedSeconds > 0 {
+		bytesPerSecond = float64(bytes-ja.throughputSampleBytes) / elapsedSeconds
+	}
+	ja.throughputSampleBytes, ja.throughputSampleTime = bytes, now
+	return bytesPerSecond
+}
+
 func (ja *jobsAdmin) UpdateTargetBandwidth(newTarget int64) {
 	if newTarget < 0 {
 		return
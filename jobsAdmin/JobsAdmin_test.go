@@ -0,0 +1,33 @@
+package jobsAdmin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/azure-storage-azcopy/v10/ste"
+)
+
+// trafficPacer reports a fixed amount of traffic; the rest of the pacer isn't used
+type trafficPacer struct {
+	ste.PacerAdmin
+	traffic int64
+}
+
+func (p *trafficPacer) GetTotalTraffic() int64 {
+	return p.traffic
+}
+
+func TestBytesPerSecondSinceLastSample(t *testing.T) {
+	a := assert.New(t)
+	pacer := &trafficPacer{traffic: 2000}
+	ja := &jobsAdmin{pacer: pacer, throughputSampleTime: time.Now().Add(-2 * time.Second)}
+
+	a.InDelta(1000, ja.BytesPerSecondSinceLastSample(), 50)
+
+	// the next sample starts where the last one ended
+	ja.throughputSampleTime = time.Now().Add(-time.Second)
+	pacer.traffic = 2500
+	a.InDelta(500, ja.BytesPerSecondSinceLastSample(), 25)
+}
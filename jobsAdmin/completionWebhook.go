@@ -0,0 +1,101 @@
+// Copyright © Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package jobsAdmin
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+)
+
+// completionWebhookTimeout bounds how long the webhook may hold up the exit of the process
+const completionWebhookTimeout = 5 * time.Second
+
+// pendingCompletionWebhooks tracks the webhooks being notified in the background
+var pendingCompletionWebhooks sync.WaitGroup
+
+// notifyCompletionWebhook notifies the webhook in the background, so that the summary isn't held up by it.
+// WaitForCompletionWebhooks must be called before exiting, for the notification not to be lost.
+func notifyCompletionWebhook(log func(common.LogLevel, string), webhookURL, secret string, js common.ListJobSummaryResponse) {
+	pendingCompletionWebhooks.Add(1)
+	go func() {
+		defer pendingCompletionWebhooks.Done()
+		postCompletionWebhook(log, webhookURL, secret, js)
+	}()
+}
+
+// WaitForCompletionWebhooks waits for the completion webhooks being notified to respond, or time out.
+func WaitForCompletionWebhooks() {
+	pendingCompletionWebhooks.Wait()
+}
+
+// CompletionWebhookSignatureHeader carries the hex encoded HMAC-SHA256 of the body, keyed with the webhook's secret
+const CompletionWebhookSignatureHeader = "X-AzCopy-Signature"
+
+// postCompletionWebhook POSTs the summary of a finished job to the webhook requested in its order.
+// Failures are logged, but don't affect the job, which has already finished anyway.
+func postCompletionWebhook(log func(common.LogLevel, string), webhookURL, secret string, js common.ListJobSummaryResponse) {
+	body, err := json.Marshal(js)
+	if err != nil {
+		log(common.LogError, fmt.Sprintf("Cannot serialize the job summary for the completion webhook: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		log(common.LogError, fmt.Sprintf("Cannot create the completion webhook request: %v", err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(CompletionWebhookSignatureHeader, "sha256="+signCompletionWebhookBody(body, secret))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log(common.LogError, fmt.Sprintf("Completion webhook %s failed: %v", common.URLStringExtension(webhookURL).RedactSecretQueryParamForLogging(), err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		log(common.LogError, fmt.Sprintf("Completion webhook %s responded with %s", common.URLStringExtension(webhookURL).RedactSecretQueryParamForLogging(), resp.Status))
+		return
+	}
+	log(common.LogInfo, "Completion webhook notified")
+}
+
+func signCompletionWebhookBody(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,93 @@
+// Copyright © Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package jobsAdmin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletionWebhookPostsSignedSummary(t *testing.T) {
+	a := assert.New(t)
+	const secret = "webhook-secret"
+
+	type webhookRequest struct {
+		method, contentType, signature string
+		body                           []byte
+	}
+	requests := make(chan webhookRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- webhookRequest{r.Method, r.Header.Get("Content-Type"), r.Header.Get(CompletionWebhookSignatureHeader), body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var logged []common.LogLevel
+	log := func(level common.LogLevel, _ string) { logged = append(logged, level) }
+
+	jobID := common.NewJobID()
+	notifyCompletionWebhook(log, server.URL+"/hook", secret, common.ListJobSummaryResponse{JobID: jobID, JobStatus: common.EJobStatus.Completed(), TotalTransfers: 3})
+	WaitForCompletionWebhooks()
+
+	// the summary is POSTed as JSON
+	request := <-requests
+	a.Equal(http.MethodPost, request.method)
+	a.Equal("application/json", request.contentType)
+	var summary common.ListJobSummaryResponse
+	a.NoError(json.Unmarshal(request.body, &summary))
+	a.Equal(jobID, summary.JobID)
+	a.Equal(common.EJobStatus.Completed(), summary.JobStatus)
+	a.Equal(uint32(3), summary.TotalTransfers)
+
+	// and signed with the secret
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(request.body)
+	a.Equal("sha256="+hex.EncodeToString(mac.Sum(nil)), request.signature)
+	a.Equal([]common.LogLevel{common.LogInfo}, logged)
+}
+
+func TestCompletionWebhookWithoutSecretIsUnsigned(t *testing.T) {
+	a := assert.New(t)
+
+	signatures := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures <- r.Header.Get(CompletionWebhookSignatureHeader)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var logged []common.LogLevel
+	postCompletionWebhook(func(level common.LogLevel, _ string) { logged = append(logged, level) }, server.URL, "", common.ListJobSummaryResponse{})
+
+	a.Equal("", <-signatures)
+	// failures are logged, not returned
+	a.Equal([]common.LogLevel{common.LogError}, logged)
+}
@@ -83,10 +83,29 @@ func MainSTE(concurrency ste.ConcurrencySettings, targetRateInMegaBitsPerSec flo
 var ExecuteNewCopyJobPartOrder =
 // ExecuteNewCopyJobPartOrder api executes a new job part order
 func(order common.CopyJobPartOrderRequest) common.CopyJobPartOrderResponse {
+	// the options are the same for every part, so they're checked once per job; checking each transfer is left
+	// to the front end's Validate, as it would slow down the submission of large jobs
+	if order.PartNum == 0 {
+		if err := order.ValidateOptions(); err != nil {
+			return common.NewCopyJobPartOrderErrorResponse([]common.JobError{{Index: common.NoTransferIndex, Message: err.Error()}})
+		}
+	}
+
 	// Get the file name for this Job Part's Plan
 	jppfn := JobsAdmin.NewJobPartPlanFileName(order.JobID, order.PartNum)
 	jppfn.Create(order)                                                                                         // Convert the order to a plan file
 	jm := JobsAdmin.JobMgrEnsureExists(order.JobID, order.LogLevel, order.CommandString, order.JobErrorHandler) // Get a this job part's job manager (create it if it doesn't exist)
+	if order.PartNum == 0 {
+		for _, warning := range order.ValidationWarnings() {
+			jm.Log(common.LogWarning, warning)
+		}
+	}
+	if order.FailFast {
+		jm.SetFailFast(true)
+	}
+	if order.CompletionWebhookURL != "" {
+		jm.SetCompletionWebhook(order.CompletionWebhookURL, string(order.CompletionWebhookSecret))
+	}
 
 	if len(order.Transfers.List) == 0 && order.IsFinalPart {
 		/*
@@ -242,6 +261,7 @@ func ResumeJobOrder(req common.ResumeJobRequest) common.CancelPauseResumeRespons
 				if jppt.TransferStatus() <= common.ETransferStatus.Failed() {
 					jppt.SetTransferStatus(common.ETransferStatus.Restarted(), true)
 					jppt.SetErrorCode(0, true)
+					jppt.SetLastHTTPStatus(0)
 				}
 			}
 		})
@@ -294,6 +314,8 @@ func GetJobSummary(jobID common.JobID) common.ListJobSummaryResponse {
 		return js
 	}
 	part0PlanStatus := part0.Plan().JobStatus()
+	setJobEndpoints(&js, part0.Plan())
+	js.SetAverageTransferSizes()
 
 	// Add on byte count from files in flight, to get a more accurate running total
 	// Check is added to prevent double counting
@@ -352,6 +374,13 @@ func GetJobSummary(jobID common.JobID) common.ListJobSummaryResponse {
 		}
 	}
 
+	// Notify in the background, so that the summary isn't held up; the front end waits for it before exiting
+	if js.JobStatus.IsJobDone() {
+		if webhookURL, webhookSecret, ok := jm.TakeCompletionWebhook(); ok {
+			notifyCompletionWebhook(jm.Log, webhookURL, webhookSecret, js)
+		}
+	}
+
 	return js
 }
 
@@ -374,6 +403,7 @@ func resurrectJobSummary(jm ste.IJobMgr) common.ListJobSummaryResponse {
 		panic(fmt.Errorf("error getting the 0th part of Job %s", jm.JobID()))
 	}
 	part0PlanStatus := part0.Plan().JobStatus()
+	setJobEndpoints(&js, part0.Plan())
 
 	// Now iterate and count things up, rebuild job summary by examining the current state of all transfers
 	jm.IterateJobParts(true, func(partNum common.PartNumber, jpm ste.IJobPartMgr) {
@@ -412,11 +442,13 @@ func resurrectJobSummary(jm ste.IJobMgr) common.ListJobSummaryResponse {
 			case common.ETransferStatus.Success():
 				js.TransfersCompleted++
 				js.TotalBytesTransferred += uint64(jppt.SourceSize)
+				js.BytesCompleted += jppt.SourceSize
 				js.TotalBytesExpected += uint64(jppt.SourceSize)
 			case common.ETransferStatus.Failed(),
 				common.ETransferStatus.TierAvailabilityCheckFailure(),
 				common.ETransferStatus.BlobTierFailure():
 				js.TransfersFailed++
+				js.BytesFailed += jppt.SourceSize
 				// getting the source and destination for failed transfer at position - index
 				src, dst, isFolder := jpp.TransferSrcDstStrings(t)
 				// appending to list of failed transfer
@@ -426,10 +458,13 @@ func resurrectJobSummary(jm ste.IJobMgr) common.ListJobSummaryResponse {
 						Dst:                dst,
 						IsFolderProperties: isFolder,
 						TransferStatus:     common.ETransferStatus.Failed(),
-						ErrorCode:          jppt.ErrorCode()}) // TODO: Optimize
+						ErrorCode:          jppt.ErrorCode(),
+						LastHTTPStatus:     jppt.LastHTTPStatus()}) // TODO: Optimize
 			case common.ETransferStatus.SkippedEntityAlreadyExists(),
-				common.ETransferStatus.SkippedBlobHasSnapshots():
+				common.ETransferStatus.SkippedBlobHasSnapshots(),
+				common.ETransferStatus.SkippedReadDenied():
 				js.TransfersSkipped++
+				js.BytesSkipped += jppt.SourceSize
 				// getting the source and destination for skipped transfer at position - index
 				src, dst, isFolder := jpp.TransferSrcDstStrings(t)
 				js.SkippedTransfers = append(js.SkippedTransfers,
@@ -444,6 +479,7 @@ func resurrectJobSummary(jm ste.IJobMgr) common.ListJobSummaryResponse {
 	})
 
 	mu.Lock()
+	js.SetAverageTransferSizes()
 	// Add on byte count from files in flight, to get a more accurate running total
 	// Check is added to prevent double counting
 	if js.TotalBytesTransferred+jm.SuccessfulBytesInActiveFiles() <= js.TotalBytesExpected {
@@ -505,6 +541,12 @@ func resurrectJobSummary(jm ste.IJobMgr) common.ListJobSummaryResponse {
 	return js
 }
 
+// setJobEndpoints fills in the redacted source and destination endpoints of the job from its part plan
+func setJobEndpoints(js *common.ListJobSummaryResponse, jpp *ste.JobPartPlanHeader) {
+	js.SourceEndpoint = common.RedactedEndpoint(string(jpp.SourceRoot[:jpp.SourceRootLength]), jpp.FromTo.From())
+	js.DestinationEndpoint = common.RedactedEndpoint(string(jpp.DestinationRoot[:jpp.DestinationRootLength]), jpp.FromTo.To())
+}
+
 // ListJobTransfers api returns the list of transfer with specific status for given jobId in http response
 func ListJobTransfers(r common.ListJobTransfersRequest) common.ListJobTransfersResponse {
 	// getJobPartInfoReferenceFromMap gives the JobPartPlanInfo Pointer for given JobId and partNumber
@@ -555,7 +597,7 @@ func ListJobTransfers(r common.ListJobTransfersRequest) common.ListJobTransfersR
 			// getting source and destination of a transfer at index index for given jobId and part number.
 			src, dst, isFolder := jpp.TransferSrcDstStrings(t)
 			ljt.Details = append(ljt.Details,
-				common.TransferDetail{Src: src, Dst: dst, IsFolderProperties: isFolder, TransferStatus: transferEntry.TransferStatus(), ErrorCode: transferEntry.ErrorCode()})
+				common.TransferDetail{Src: src, Dst: dst, IsFolderProperties: isFolder, TransferStatus: transferEntry.TransferStatus(), ErrorCode: transferEntry.ErrorCode(), LastHTTPStatus: transferEntry.LastHTTPStatus()})
 		}
 	}
 	return ljt
@@ -591,12 +633,128 @@ func GetJobDetails(r common.GetJobDetailsRequest) common.GetJobDetailsResponse {
 		}
 	}
 
+	var invocationTime time.Time
+	if jp0.Plan().InvocationTime != 0 {
+		invocationTime = time.Unix(0, jp0.Plan().InvocationTime)
+	}
+
 	return common.GetJobDetailsResponse{
-		ErrorMsg:    "",
-		FromTo:      jp0.Plan().FromTo,
-		Source:      source,
-		Destination: destination,
-		TrailingDot: jp0.Plan().DstFileData.TrailingDot,
+		ErrorMsg:       "",
+		FromTo:         jp0.Plan().FromTo,
+		Source:         source,
+		Destination:    destination,
+		TrailingDot:    jp0.Plan().DstFileData.TrailingDot,
+		CommandString:  jp0.Plan().CommandString(),
+		InvocationTime: invocationTime,
+	}
+}
+
+// AggregateThroughput api sums up the throughput of every job in progress, over the time since it was last called.
+func AggregateThroughput() common.AggregateThroughputResponse {
+	resp := common.AggregateThroughputResponse{TotalBytesPerSecond: JobsAdmin.BytesPerSecondSinceLastSample()}
+	for _, jobID := range JobsAdmin.JobIDs() {
+		jm, found := JobsAdmin.JobMgr(jobID)
+		if !found {
+			continue
+		}
+		part0, ok := jm.JobPartMgr(0)
+		if !ok || part0.Plan().JobStatus() != common.EJobStatus.InProgress() {
+			continue
+		}
+		resp.ActiveJobs++
+		resp.TotalInFlightBytes += int64(jm.SuccessfulBytesInActiveFiles())
+	}
+	return resp
+}
+
+// ExportJobPlan api returns the job's plan, i.e. all of its transfers and the options they were planned with,
+// with any credentials redacted.
+func ExportJobPlan(r common.ExportJobPlanRequest) common.JobPlanExport {
+	jm, found := JobsAdmin.JobMgr(r.JobID)
+	if !found {
+		// Job with JobId does not exists.
+		// Search the plan files in Azcopy folder and resurrect the Job.
+		if !JobsAdmin.ResurrectJob(r.JobID, nil, nil, false, warnJobErrorHandler{jobID: r.JobID}) {
+			return common.JobPlanExport{
+				ErrorMsg: fmt.Sprintf("Job with JobID %v does not exist or is invalid", r.JobID),
+			}
+		}
+		jm, _ = JobsAdmin.JobMgr(r.JobID)
+	}
+
+	jp0, ok := jm.JobPartMgr(0)
+	if !ok {
+		return common.JobPlanExport{
+			ErrorMsg: fmt.Sprintf("error getting the plan of the job with JobID %v", r.JobID),
+		}
+	}
+
+	export := common.JobPlanExport{Meta: planOrder(jp0.Plan())}
+	for partNum := ste.PartNumber(0); true; partNum++ {
+		jpm, found := jm.JobPartMgr(partNum)
+		if !found {
+			break
+		}
+		jpp := jpm.Plan()
+		export.Meta.IsFinalPart = jpp.IsFinalPart
+		for t := uint32(0); t < jpp.NumTransfers; t++ {
+			transferEntry := jpp.Transfer(t)
+			src, dst := jpp.TransferSrcDstRelatives(t)
+			export.Transfers = append(export.Transfers, common.CopyTransfer{
+				Source:           src,
+				Destination:      dst,
+				EntityType:       transferEntry.EntityType,
+				LastModifiedTime: time.Unix(0, transferEntry.ModifiedTime),
+				SourceSize:       transferEntry.SourceSize,
+			})
+		}
+	}
+	return export.Redacted()
+}
+
+// planOrder rebuilds the options of the order a job part was planned from, as far as its plan keeps them.
+func planOrder(jpp *ste.JobPartPlanHeader) common.CopyJobPartOrderRequest {
+	var invocationTime time.Time
+	if jpp.InvocationTime != 0 {
+		invocationTime = time.Unix(0, jpp.InvocationTime)
+	}
+	return common.CopyJobPartOrderRequest{
+		Version:             jpp.Version,
+		JobID:               jpp.JobID,
+		ForceWrite:          jpp.ForceWrite,
+		ForceIfReadOnly:     jpp.ForceIfReadOnly,
+		AutoDecompress:      jpp.AutoDecompress,
+		Priority:            jpp.Priority,
+		FromTo:              jpp.FromTo,
+		Fpo:                 jpp.Fpo,
+		SymlinkHandlingType: jpp.SymlinkHandling,
+		SourceRoot: common.ResourceString{
+			Value:      string(jpp.SourceRoot[:jpp.SourceRootLength]),
+			ExtraQuery: string(jpp.SourceExtraQuery[:jpp.SourceExtraQueryLength]),
+		},
+		DestinationRoot: common.ResourceString{
+			Value:      string(jpp.DestinationRoot[:jpp.DestinationRootLength]),
+			ExtraQuery: string(jpp.DestExtraQuery[:jpp.DestExtraQueryLength]),
+		},
+		LogLevel:                       jpp.LogLevel,
+		CommandString:                  jpp.CommandString(),
+		InvocationTime:                 invocationTime,
+		PreservePermissions:            jpp.PreservePermissions,
+		PreserveInfo:                   jpp.PreserveInfo,
+		PreservePOSIXProperties:        jpp.PreservePOSIXProperties,
+		S2SGetPropertiesInBackend:      jpp.S2SGetPropertiesInBackend,
+		S2SSourceChangeValidation:      jpp.S2SSourceChangeValidation,
+		DestLengthValidation:           jpp.DestLengthValidation,
+		S2SInvalidMetadataHandleOption: jpp.S2SInvalidMetadataHandleOption,
+		BlobFSRecursiveDelete:          jpp.BlobFSRecursiveDelete,
+		MetadataOnly:                   jpp.MetadataOnly,
+		RequesterPays:                  jpp.RequesterPays,
+		CheckpointIntervalSeconds:      jpp.CheckpointIntervalSeconds,
+		OverwriteMtimeToleranceSeconds: jpp.OverwriteMtimeToleranceSeconds,
+		UserAgentSuffix:                string(jpp.UserAgentSuffix[:jpp.UserAgentSuffixLength]),
+		ErrorOnReadDenied:              jpp.ErrorOnReadDenied,
+		SourceRegion:                   string(jpp.SourceRegion[:jpp.SourceRegionLength]),
+		OverwriteCheckMode:             jpp.OverwriteCheckMode,
 	}
 }
 
@@ -29,12 +29,14 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // These methods read common.AzcopyJobPlanFolder and common.LogPathFolder to list and remove job plan files and logs.
 
-// ListJobs returns the jobId of all the jobs existing in the current instance of azcopy
-func ListJobs(givenStatus common.JobStatus) common.ListJobsResponse {
+// ListJobs returns the jobId of all the jobs existing in the current instance of azcopy.
+// With includeSummaries, a lightweight summary of each listed job is returned too; see lightweightJobSummary.
+func ListJobs(givenStatus common.JobStatus, includeSummaries bool) common.ListJobsResponse {
 	ret := common.ListJobsResponse{JobIDDetails: []common.JobIDDetails{}}
 	files := func(ext string) []os.FileInfo {
 		var files []os.FileInfo
@@ -47,6 +49,18 @@ func ListJobs(givenStatus common.JobStatus) common.ListJobsResponse {
 		return files
 	}(fmt.Sprintf(".steV%d", ste.DataSchemaVersion))
 
+	// the summaries need every part of the job, not just the 0th
+	jobParts := map[common.JobID][]ste.JobPartPlanFileName{}
+	if includeSummaries {
+		ret.JobSummaries = []common.ListJobSummaryResponse{}
+		for f := 0; f < len(files); f++ {
+			planFile := ste.JobPartPlanFileName(files[f].Name())
+			if jobID, _, err := planFile.Parse(); err == nil {
+				jobParts[jobID] = append(jobParts[jobID], planFile)
+			}
+		}
+	}
+
 	// TODO : sort the file.
 	for f := 0; f < len(files); f++ {
 		planFile := ste.JobPartPlanFileName(files[f].Name())
@@ -62,6 +76,9 @@ func ListJobs(givenStatus common.JobStatus) common.ListJobsResponse {
 			ret.JobIDDetails = append(ret.JobIDDetails,
 				common.JobIDDetails{JobId: jobID, CommandString: jpph.CommandString(),
 					StartTime: jpph.StartTime, JobStatus: jpph.JobStatus()})
+			if includeSummaries {
+				ret.JobSummaries = append(ret.JobSummaries, lightweightJobSummary(jobID, jpph, jobParts[jobID]))
+			}
 		}
 
 		mmf.Unmap()
@@ -70,6 +87,57 @@ func ListJobs(givenStatus common.JobStatus) common.ListJobsResponse {
 	return ret
 }
 
+// lightweightJobSummary summarizes a job's status and progress straight from its plan files, without resurrecting it.
+// Unlike GetJobSummary, it doesn't list the failed and skipped transfers, nor account for bytes of files in flight.
+func lightweightJobSummary(jobID common.JobID, part0 *ste.JobPartPlanHeader, parts []ste.JobPartPlanFileName) common.ListJobSummaryResponse {
+	js := common.ListJobSummaryResponse{
+		Timestamp: time.Now().UTC(),
+		JobID:     jobID,
+		JobStatus: part0.JobStatus(),
+	}
+	setJobEndpoints(&js, part0)
+
+	for _, planFile := range parts {
+		mmf := planFile.Map()
+		jpp := mmf.Plan()
+		js.CompleteJobOrdered = js.CompleteJobOrdered || jpp.IsFinalPart
+		js.TotalTransfers += jpp.NumTransfers
+
+		for t := uint32(0); t < jpp.NumTransfers; t++ {
+			jppt := jpp.Transfer(t)
+			js.TotalBytesEnumerated += uint64(jppt.SourceSize)
+
+			switch jppt.TransferStatus() {
+			case common.ETransferStatus.Success():
+				js.TransfersCompleted++
+				js.TotalBytesTransferred += uint64(jppt.SourceSize)
+				js.BytesCompleted += jppt.SourceSize
+				js.TotalBytesExpected += uint64(jppt.SourceSize)
+			case common.ETransferStatus.Failed(),
+				common.ETransferStatus.TierAvailabilityCheckFailure(),
+				common.ETransferStatus.BlobTierFailure():
+				js.TransfersFailed++
+				js.BytesFailed += jppt.SourceSize
+			case common.ETransferStatus.SkippedEntityAlreadyExists(),
+				common.ETransferStatus.SkippedBlobHasSnapshots(),
+				common.ETransferStatus.SkippedReadDenied():
+				js.TransfersSkipped++
+				js.BytesSkipped += jppt.SourceSize
+			default:
+				js.TotalBytesExpected += uint64(jppt.SourceSize)
+			}
+		}
+		mmf.Unmap()
+	}
+
+	if js.TotalBytesExpected == 0 {
+		js.PercentComplete = 100
+	} else {
+		js.PercentComplete = 100 * float32(js.TotalBytesTransferred) / float32(js.TotalBytesExpected)
+	}
+	return js
+}
+
 // TODO (gapra): Re-evaluate the need for currentJobID.
 
 // DeleteAllJobFilesExceptCurrent removes all job plan files and log files in the specified folders.
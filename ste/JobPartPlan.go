@@ -13,12 +13,15 @@ import (
 // dataSchemaVersion defines the data schema version of JobPart order files supported by
 // current version of azcopy
 // To be Incremented every time when we release azcopy with changed dataSchema
-const DataSchemaVersion common.Version = 19
+const DataSchemaVersion common.Version = 20
 
 const (
 	CustomHeaderMaxBytes = 256
 	MetadataMaxBytes     = 1000 // If > 65536, then jobPartPlanBlobData's MetadataLength field's type must change
-	BlobTagsMaxByte      = 4000
+	BlobTagsMaxByte      = common.MaxBlobTagsBytes
+	CustomHeadersMaxByte = common.MaxCustomHeadersBytes
+
+	BlobTypeOverridesMaxByte = common.MaxBlobTypeOverridesBytes
 )
 
 // //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -31,7 +34,8 @@ func (mmf *JobPartPlanMMF) Plan() *JobPartPlanHeader {
 	slice := (*common.MMF)(mmf).Slice()
 	return (*JobPartPlanHeader)(unsafe.Pointer(&slice[0]))
 }
-func (mmf *JobPartPlanMMF) Unmap() { (*common.MMF)(mmf).Unmap() }
+func (mmf *JobPartPlanMMF) Unmap()               { (*common.MMF)(mmf).Unmap() }
+func (mmf *JobPartPlanMMF) Flush() (bool, error) { return (*common.MMF)(mmf).Flush() }
 
 // //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
@@ -90,6 +94,45 @@ type JobPartPlanHeader struct {
 	S2SInvalidMetadataHandleOption common.InvalidMetadataHandleOption
 	// BlobFSRecursiveDelete represents whether the user wants to make a recursive call to the DFS endpoint or not
 	BlobFSRecursiveDelete bool
+	// MetadataOnly represents whether only the metadata and tier of the destination are set, without copying any data
+	MetadataOnly bool
+	// InvocationTime is when the user invoked the command (in nanoseconds since the epoch), 0 if unknown
+	InvocationTime int64
+	// RequesterPays represents whether the S3 source is read with the x-amz-request-payer header set
+	RequesterPays bool
+
+	// CheckpointIntervalSeconds represents how often the plan file is flushed to disk; 0 leaves it to the OS
+	CheckpointIntervalSeconds uint32
+
+	// OverwriteMtimeToleranceSeconds represents how far apart last modified times may be and still be equal, when overwriting if the source is newer
+	OverwriteMtimeToleranceSeconds uint32
+
+	// UserAgentSuffix is appended to the user agent of the requests to Azure Storage
+	UserAgentSuffixLength uint16
+	UserAgentSuffix       [common.MaxUserAgentSuffixLength]byte
+
+	// ErrorOnReadDenied represents whether local files that can't be read fail their transfers, instead of being skipped
+	ErrorOnReadDenied bool
+
+	// SourceRegion is the region requests to an S3 source are signed for, when its URL doesn't name one
+	SourceRegionLength uint16
+	SourceRegion       [common.MaxS3RegionLength]byte
+
+	// OverwriteCheckMode represents when the destinations' existence is checked before overwrite decisions
+	OverwriteCheckMode common.OverwriteCheckMode
+
+	// LineEndingMode represents how the line endings of the uploaded files with the TextExtensions are translated
+	LineEndingMode       common.LineEndingMode
+	TextExtensionsLength uint16
+	TextExtensions       [common.MaxTextExtensionsBytes]byte
+
+	// MaxPerTransferRetries represents how many retries each transfer's requests may make in total; 0 leaves it to the engine
+	MaxPerTransferRetries uint32
+
+	// MoveSource represents whether each source is deleted once it's been copied successfully
+	MoveSource bool
+	// HTTPVersionPreference represents the HTTP version of the connections to an S3 source
+	HTTPVersionPreference common.HTTPVersionPreference
 
 	// Any fields below this comment are NOT constants; they may change over as the job part is processed.
 	// Care must be taken to read/write to these fields in a thread-safe way!
@@ -324,6 +367,29 @@ type JobPartPlanDstBlob struct {
 	SetPropertiesFlags common.SetPropertiesFlags
 
 	DeleteDestinationFileIfNecessary bool
+
+	// Extra headers sent with each request of the transfers, encoded as a query string
+	CustomHeadersLength uint16
+	CustomHeaders       [CustomHeadersMaxByte]byte
+
+	// Specifies how uploads are compressed, if at all
+	CompressionAlgorithm common.CompressionAlgorithm
+
+	// Specifies whether S3 sources' object tags are carried over, when no tags are given explicitly
+	PreserveTags bool
+
+	// Specifies whether the source's last modified time is recorded in the destination's metadata
+	PreserveSourceTimeAsMetadata bool
+
+	// Blob types chosen by file extension in place of BlobType, encoded as a query string
+	BlobTypeOverridesLength uint16
+	BlobTypeOverrides       [BlobTypeOverridesMaxByte]byte
+
+	// Specifies how S3 sources' metadata keys that differ only by case are carried over
+	MetadataKeyCasePolicy common.MetadataKeyCasePolicy
+
+	// Specifies whether the sources' content disposition is carried over when ContentDisposition is empty
+	PreserveContentDisposition bool
 }
 
 // JobPartPlanDstFile holds additional settings required when the destination is a file
@@ -394,6 +460,10 @@ type JobPartPlanTransfer struct {
 	// atomicErrorCode has a default value (0) which means either there was no error or transfer failed because some non storageError.
 	// atomicErrorCode should not be directly accessed anywhere except by transferStatus and setTransferStatus
 	atomicErrorCode int32
+
+	// atomicLastHTTPStatus is the HTTP status of the transfer's last failed attempt, or 0 if none failed.
+	// Unlike atomicErrorCode, it's overwritten by every failure.
+	atomicLastHTTPStatus int32
 }
 
 // TransferStatus returns the transfer's status
@@ -422,6 +492,16 @@ func (jppt *JobPartPlanTransfer) ErrorCode() int32 {
 	return atomic.LoadInt32(&jppt.atomicErrorCode)
 }
 
+// LastHTTPStatus returns the HTTP status of the transfer's last failed attempt.
+func (jppt *JobPartPlanTransfer) LastHTTPStatus() int32 {
+	return atomic.LoadInt32(&jppt.atomicLastHTTPStatus)
+}
+
+// SetLastHTTPStatus records the HTTP status of the transfer's latest failed attempt; 0 clears it.
+func (jppt *JobPartPlanTransfer) SetLastHTTPStatus(status int32) {
+	atomic.StoreInt32(&jppt.atomicLastHTTPStatus, status)
+}
+
 // SetErrorCode sets the error code of the error if transfer failed.
 // overWrite flags if set to true overWrites the atomicErrorCode.
 // If overWrite flag is set to false, then errorCode won't be overwritten.
@@ -115,8 +115,27 @@ func (jpfn JobPartPlanFileName) Create(order common.CopyJobPartOrderRequest) {
 	if len(order.BlobAttributes.Metadata) > len(JobPartPlanDstBlob{}.Metadata) {
 		panic(fmt.Errorf("metadata string is too large: %q", order.BlobAttributes.Metadata))
 	}
-	if len(order.BlobAttributes.BlobTagsString) > len(JobPartPlanDstBlob{}.BlobTags) {
-		panic(fmt.Errorf("blob tags string is too large: %q", order.BlobAttributes.BlobTagsString))
+	blobTagsString := order.BlobAttributes.EncodedTags()
+	if len(blobTagsString) > len(JobPartPlanDstBlob{}.BlobTags) {
+		panic(fmt.Errorf("blob tags string is too large: %q", blobTagsString))
+	}
+	customHeadersString := order.BlobAttributes.EncodedCustomHeaders()
+	if len(customHeadersString) > len(JobPartPlanDstBlob{}.CustomHeaders) {
+		panic(fmt.Errorf("custom headers string is too large: %q", customHeadersString))
+	}
+	if len(order.UserAgentSuffix) > len(JobPartPlanHeader{}.UserAgentSuffix) {
+		panic(fmt.Errorf("user agent suffix is too large: %q", order.UserAgentSuffix))
+	}
+	if len(order.SourceRegion) > len(JobPartPlanHeader{}.SourceRegion) {
+		panic(fmt.Errorf("source region is too large: %q", order.SourceRegion))
+	}
+	blobTypeOverridesString := order.BlobAttributes.EncodedBlobTypeOverrides()
+	if len(blobTypeOverridesString) > len(JobPartPlanDstBlob{}.BlobTypeOverrides) {
+		panic(fmt.Errorf("blob type overrides string is too large: %q", blobTypeOverridesString))
+	}
+	textExtensionsString := order.BlobAttributes.EncodedTextExtensions()
+	if len(textExtensionsString) > len(JobPartPlanHeader{}.TextExtensions) {
+		panic(fmt.Errorf("text extensions string is too large: %q", textExtensionsString))
 	}
 
 	// This nested function writes a structure value to an io.Writer & returns the number of bytes written
@@ -167,7 +186,13 @@ func (jpfn JobPartPlanFileName) Create(order common.CopyJobPartOrderRequest) {
 	//		panic(errors.New("unrecognized blob type"))
 	//	}*/
 	// }
-	putBlobSize := order.BlobAttributes.PutBlobSizeInBytes
+	putBlobSize := order.BlobAttributes.SingleShotThreshold()
+	// never persist secrets that may have been part of the command line
+	commandString := common.RedactCommandString(order.CommandString)
+	invocationTime := int64(0)
+	if !order.InvocationTime.IsZero() {
+		invocationTime = order.InvocationTime.UnixNano()
+	}
 	// Initialize the Job Part's Plan header
 	jpph := JobPartPlanHeader{
 		Version:                DataSchemaVersion,
@@ -186,7 +211,7 @@ func (jpfn JobPartPlanFileName) Create(order common.CopyJobPartOrderRequest) {
 		TTLAfterCompletion:     uint32(time.Time{}.Nanosecond()),
 		FromTo:                 order.FromTo,
 		Fpo:                    order.Fpo,
-		CommandStringLength:    uint32(len(order.CommandString)),
+		CommandStringLength:    uint32(len(commandString)),
 		NumTransfers:           uint32(len(order.Transfers.List)),
 		LogLevel:               order.LogLevel,
 		DstBlobData: JobPartPlanDstBlob{
@@ -203,12 +228,19 @@ func (jpfn JobPartPlanFileName) Create(order common.CopyJobPartOrderRequest) {
 			MetadataLength:                   uint16(len(order.BlobAttributes.Metadata)),
 			BlockSize:                        blockSize,
 			PutBlobSize:                      putBlobSize,
-			BlobTagsLength:                   uint16(len(order.BlobAttributes.BlobTagsString)),
+			BlobTagsLength:                   uint16(len(blobTagsString)),
 			CpkInfo:                          order.CpkOptions.CpkInfo,
 			CpkScopeInfoLength:               uint16(len(order.CpkOptions.CpkScopeInfo)),
 			IsSourceEncrypted:                order.CpkOptions.IsSourceEncrypted,
 			SetPropertiesFlags:               order.SetPropertiesFlags,
 			DeleteDestinationFileIfNecessary: order.BlobAttributes.DeleteDestinationFileIfNecessary,
+			CustomHeadersLength:              uint16(len(customHeadersString)),
+			CompressionAlgorithm:             order.BlobAttributes.UploadCompression(),
+			PreserveTags:                     order.BlobAttributes.PreserveTags,
+			PreserveSourceTimeAsMetadata:     order.BlobAttributes.PreserveSourceTimeAsMetadata,
+			BlobTypeOverridesLength:          uint16(len(blobTypeOverridesString)),
+			MetadataKeyCasePolicy:            order.BlobAttributes.MetadataKeyCasePolicy,
+			PreserveContentDisposition:       order.BlobAttributes.PreserveContentDisposition,
 		},
 		DstLocalData: JobPartPlanDstLocal{
 			PreserveLastModifiedTime: order.BlobAttributes.PreserveLastModifiedTime,
@@ -216,13 +248,27 @@ func (jpfn JobPartPlanFileName) Create(order common.CopyJobPartOrderRequest) {
 		},
 		PreservePermissions:     order.PreservePermissions,
 		PreserveInfo:            order.PreserveInfo,
-		PreservePOSIXProperties: order.PreservePOSIXProperties,
+		PreservePOSIXProperties: order.PreservesPOSIXProperties(),
 		// For S2S copy, per JobPartPlan info
 		S2SGetPropertiesInBackend:      order.S2SGetPropertiesInBackend,
 		S2SSourceChangeValidation:      order.S2SSourceChangeValidation,
 		S2SInvalidMetadataHandleOption: order.S2SInvalidMetadataHandleOption,
 		DestLengthValidation:           order.DestLengthValidation,
 		BlobFSRecursiveDelete:          order.BlobFSRecursiveDelete,
+		MetadataOnly:                   order.MetadataOnly,
+		InvocationTime:                 invocationTime,
+		RequesterPays:                  order.RequesterPays,
+		CheckpointIntervalSeconds:      order.CheckpointIntervalSeconds,
+		OverwriteMtimeToleranceSeconds: order.OverwriteMtimeToleranceSeconds,
+		UserAgentSuffixLength:          uint16(len(order.UserAgentSuffix)),
+		ErrorOnReadDenied:              order.ErrorOnReadDenied,
+		SourceRegionLength:             uint16(len(order.SourceRegion)),
+		OverwriteCheckMode:             order.OverwriteCheckMode,
+		LineEndingMode:                 order.BlobAttributes.LineEndingMode,
+		TextExtensionsLength:           uint16(len(textExtensionsString)),
+		MaxPerTransferRetries:          order.BlobAttributes.MaxPerTransferRetries,
+		MoveSource:                     order.MoveSource,
+		HTTPVersionPreference:          order.HTTPVersionPreference,
 		atomicJobStatus:                common.EJobStatus.InProgress(), // We default to InProgress
 		DeleteSnapshotsOption:          order.BlobAttributes.DeleteSnapshotsOption,
 		PermanentDeleteOption:          order.BlobAttributes.PermanentDeleteOption,
@@ -245,13 +291,18 @@ func (jpfn JobPartPlanFileName) Create(order common.CopyJobPartOrderRequest) {
 	copy(jpph.DstBlobData.ContentDisposition[:], order.BlobAttributes.ContentDisposition)
 	copy(jpph.DstBlobData.CacheControl[:], order.BlobAttributes.CacheControl)
 	copy(jpph.DstBlobData.Metadata[:], order.BlobAttributes.Metadata)
-	copy(jpph.DstBlobData.BlobTags[:], order.BlobAttributes.BlobTagsString)
+	copy(jpph.DstBlobData.BlobTags[:], blobTagsString)
 	copy(jpph.DstBlobData.CpkScopeInfo[:], order.CpkOptions.CpkScopeInfo)
+	copy(jpph.DstBlobData.CustomHeaders[:], customHeadersString)
+	copy(jpph.DstBlobData.BlobTypeOverrides[:], blobTypeOverridesString)
+	copy(jpph.UserAgentSuffix[:], order.UserAgentSuffix)
+	copy(jpph.SourceRegion[:], order.SourceRegion)
+	copy(jpph.TextExtensions[:], textExtensionsString)
 
 	eof += writeValue(file, &jpph)
 
 	// write the command string in the JobPart Plan file
-	bytesWritten, err := file.WriteString(order.CommandString)
+	bytesWritten, err := file.WriteString(commandString)
 	if err != nil {
 		panic(err)
 	}
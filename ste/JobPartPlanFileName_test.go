@@ -0,0 +1,282 @@
+// Copyright © Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ste
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// createTestPlan writes the plan file of a single part job from order, and maps it in.
+func createTestPlan(t *testing.T, order common.CopyJobPartOrderRequest) *JobPartPlanMMF {
+	oldPlanFolder := common.AzcopyJobPlanFolder
+	common.AzcopyJobPlanFolder = t.TempDir()
+	t.Cleanup(func() { common.AzcopyJobPlanFolder = oldPlanFolder })
+
+	order.JobID = common.NewJobID()
+	order.IsFinalPart = true
+	if order.SourceRoot.Value == "" {
+		order.FromTo = common.EFromTo.LocalBlob()
+		order.SourceRoot = common.ResourceString{Value: "/tmp/source"}
+		order.DestinationRoot = common.ResourceString{Value: "https://account.blob.core.windows.net/container"}
+	}
+	if len(order.Transfers.List) == 0 {
+		order.Transfers = common.Transfers{
+			List:              []common.CopyTransfer{{Source: "file.txt", Destination: "file.txt", SourceSize: 10, EntityType: common.EEntityType.File()}},
+			TotalSizeInBytes:  10,
+			FileTransferCount: 1,
+		}
+	}
+
+	planFile := JobPartPlanFileName(fmt.Sprintf(JobPartPlanFileNameFormat, order.JobID.String(), 0, DataSchemaVersion))
+	planFile.Create(order)
+	mmf := planFile.Map()
+	t.Cleanup(mmf.Unmap)
+	return mmf
+}
+
+func TestCreatePersistsCheckpointInterval(t *testing.T) {
+	a := assert.New(t)
+
+	mmf := createTestPlan(t, common.CopyJobPartOrderRequest{CheckpointIntervalSeconds: 30})
+	a.Equal(uint32(30), mmf.Plan().CheckpointIntervalSeconds)
+
+	mapped, err := mmf.Flush()
+	a.True(mapped)
+	a.NoError(err)
+
+	mmf = createTestPlan(t, common.CopyJobPartOrderRequest{})
+	a.Equal(uint32(0), mmf.Plan().CheckpointIntervalSeconds)
+}
+
+func TestCreatePersistsUserAgentSuffix(t *testing.T) {
+	a := assert.New(t)
+
+	plan := createTestPlan(t, common.CopyJobPartOrderRequest{UserAgentSuffix: "team/data-platform"}).Plan()
+	a.Equal("team/data-platform", string(plan.UserAgentSuffix[:plan.UserAgentSuffixLength]))
+
+	plan = createTestPlan(t, common.CopyJobPartOrderRequest{}).Plan()
+	a.Zero(plan.UserAgentSuffixLength)
+}
+
+func TestCreatePersistsOverwriteMtimeTolerance(t *testing.T) {
+	a := assert.New(t)
+
+	mmf := createTestPlan(t, common.CopyJobPartOrderRequest{ForceWrite: common.EOverwriteOption.IfSourceNewer(), OverwriteMtimeToleranceSeconds: 2})
+	jpm := &jobPartMgr{planMMF: mmf}
+	a.Equal(common.EOverwriteOption.IfSourceNewer(), jpm.GetOverwriteOption())
+	a.Equal(uint32(2), jpm.GetOverwriteMtimeToleranceSeconds())
+}
+
+func TestTransferLastHTTPStatus(t *testing.T) {
+	a := assert.New(t)
+
+	transfer := createTestPlan(t, common.CopyJobPartOrderRequest{}).Plan().Transfer(0)
+	// none until an attempt fails, so successful transfers report 0
+	a.Zero(transfer.LastHTTPStatus())
+
+	transfer.SetLastHTTPStatus(503)
+	transfer.SetLastHTTPStatus(403)
+	a.Equal(int32(403), transfer.LastHTTPStatus())
+
+	transfer.SetLastHTTPStatus(0)
+	a.Zero(transfer.LastHTTPStatus())
+}
+
+func TestCreatePersistsErrorOnReadDenied(t *testing.T) {
+	a := assert.New(t)
+
+	jpm := &jobPartMgr{planMMF: createTestPlan(t, common.CopyJobPartOrderRequest{ErrorOnReadDenied: true})}
+	a.True(jpm.ErrorOnReadDenied())
+
+	// unreadable files are skipped by default
+	jpm = &jobPartMgr{planMMF: createTestPlan(t, common.CopyJobPartOrderRequest{})}
+	a.False(jpm.ErrorOnReadDenied())
+}
+
+func TestCreatePersistsSourceRegion(t *testing.T) {
+	a := assert.New(t)
+	order := common.CopyJobPartOrderRequest{
+		FromTo:          common.EFromTo.S3Blob(),
+		SourceRoot:      common.ResourceString{Value: "https://bucket.s3.amazonaws.com"},
+		DestinationRoot: common.ResourceString{Value: "https://account.blob.core.windows.net/container"},
+		Transfers: common.Transfers{List: []common.CopyTransfer{{
+			Source: "/key", Destination: "/key", SourceSize: 10, EntityType: common.EEntityType.File(),
+		}}},
+		SourceRegion: "eu-central-1",
+	}
+
+	mmf := createTestPlan(t, order)
+	jptm := &jobPartTransferMgr{jobPartMgr: &jobPartMgr{planMMF: mmf}, jobPartPlanTransfer: mmf.Plan().Transfer(0)}
+	a.Equal("eu-central-1", jptm.Info().SourceRegion)
+}
+
+func TestCreatePersistsOverwriteCheckMode(t *testing.T) {
+	a := assert.New(t)
+
+	jpm := &jobPartMgr{planMMF: createTestPlan(t, common.CopyJobPartOrderRequest{OverwriteCheckMode: common.EOverwriteCheckMode.Never()})}
+	a.Equal(common.EOverwriteCheckMode.Never(), jpm.OverwriteCheckMode())
+
+	jpm = &jobPartMgr{planMMF: createTestPlan(t, common.CopyJobPartOrderRequest{})}
+	a.Equal(common.EOverwriteCheckMode.OnlyIfPolicyNeeds(), jpm.OverwriteCheckMode())
+}
+
+func TestCreatePersistsLineEndingMode(t *testing.T) {
+	a := assert.New(t)
+
+	jpm := &jobPartMgr{planMMF: createTestPlan(t, common.CopyJobPartOrderRequest{BlobAttributes: common.BlobTransferAttributes{
+		LineEndingMode: common.ELineEndingMode.ToLF(),
+		TextExtensions: []string{"txt", ".CSV"},
+	}})}
+	a.Equal(common.ELineEndingMode.ToLF(), jpm.LineEndingMode("dir/notes.txt"))
+	a.Equal(common.ELineEndingMode.ToLF(), jpm.LineEndingMode("dir/data.csv"))
+	// other files are left alone
+	a.Equal(common.ELineEndingMode.None(), jpm.LineEndingMode("dir/image.png"))
+	a.Equal(common.ELineEndingMode.None(), jpm.LineEndingMode("dir/txt"))
+
+	jpm = &jobPartMgr{planMMF: createTestPlan(t, common.CopyJobPartOrderRequest{})}
+	a.Equal(common.ELineEndingMode.None(), jpm.LineEndingMode("dir/notes.txt"))
+}
+
+func TestCreatePersistsMaxPerTransferRetries(t *testing.T) {
+	a := assert.New(t)
+
+	mmf := createTestPlan(t, common.CopyJobPartOrderRequest{BlobAttributes: common.BlobTransferAttributes{MaxPerTransferRetries: 3}})
+	a.Equal(uint32(3), mmf.Plan().MaxPerTransferRetries)
+}
+
+func TestCreatePersistsSingleShotThreshold(t *testing.T) {
+	a := assert.New(t)
+
+	mmf := createTestPlan(t, common.CopyJobPartOrderRequest{BlobAttributes: common.BlobTransferAttributes{SingleShotThresholdBytes: 64 * 1024 * 1024}})
+	a.Equal(int64(64*1024*1024), mmf.Plan().DstBlobData.PutBlobSize)
+
+	mmf = createTestPlan(t, common.CopyJobPartOrderRequest{BlobAttributes: common.BlobTransferAttributes{PutBlobSizeInBytes: 32 * 1024 * 1024}})
+	a.Equal(int64(32*1024*1024), mmf.Plan().DstBlobData.PutBlobSize)
+}
+
+func TestPreserveSourceTimeAsMetadata(t *testing.T) {
+	a := assert.New(t)
+	lastModified := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	order := common.CopyJobPartOrderRequest{
+		FromTo:          common.EFromTo.S3Blob(),
+		SourceRoot:      common.ResourceString{Value: "https://bucket.s3.amazonaws.com"},
+		DestinationRoot: common.ResourceString{Value: "https://account.blob.core.windows.net/container"},
+		Transfers: common.Transfers{List: []common.CopyTransfer{{
+			Source: "/key", Destination: "/key", SourceSize: 10, EntityType: common.EEntityType.File(),
+			LastModifiedTime: lastModified, Metadata: common.Metadata{"owner": to.Ptr("team")},
+		}}},
+		BlobAttributes: common.BlobTransferAttributes{PreserveSourceTimeAsMetadata: true},
+	}
+	info := func(mmf *JobPartPlanMMF) *TransferInfo {
+		jptm := &jobPartTransferMgr{jobPartMgr: &jobPartMgr{planMMF: mmf}, jobPartPlanTransfer: mmf.Plan().Transfer(0)}
+		return jptm.Info()
+	}
+
+	// the source's time is added to its metadata
+	mmf := createTestPlan(t, order)
+	a.True(mmf.Plan().DstBlobData.PreserveSourceTimeAsMetadata)
+	transferInfo := info(mmf)
+	a.True(transferInfo.PreserveSourceTimeAsMetadata)
+	a.Equal("team", *transferInfo.SrcMetadata["owner"])
+	a.Equal("2024-03-01T12:30:00Z", *transferInfo.SrcMetadata[common.SourceLastModifiedMetadataKey])
+
+	// but only when asked to
+	order.BlobAttributes.PreserveSourceTimeAsMetadata = false
+	transferInfo = info(createTestPlan(t, order))
+	a.Equal(common.Metadata{"owner": to.Ptr("team")}, transferInfo.SrcMetadata)
+}
+
+func TestPreserveContentDisposition(t *testing.T) {
+	a := assert.New(t)
+	order := common.CopyJobPartOrderRequest{
+		FromTo:          common.EFromTo.S3Blob(),
+		SourceRoot:      common.ResourceString{Value: "https://bucket.s3.amazonaws.com"},
+		DestinationRoot: common.ResourceString{Value: "https://account.blob.core.windows.net/container"},
+		Transfers: common.Transfers{List: []common.CopyTransfer{{
+			Source: "/key", Destination: "/key", SourceSize: 10, EntityType: common.EEntityType.File(),
+			ContentDisposition: "attachment",
+		}}},
+		BlobAttributes: common.BlobTransferAttributes{PreserveContentDisposition: true},
+	}
+	info := func(order common.CopyJobPartOrderRequest) *TransferInfo {
+		mmf := createTestPlan(t, order)
+		jptm := &jobPartTransferMgr{jobPartMgr: &jobPartMgr{planMMF: mmf}, jobPartPlanTransfer: mmf.Plan().Transfer(0)}
+		return jptm.Info()
+	}
+
+	// the source's disposition is carried over
+	transferInfo := info(order)
+	a.True(transferInfo.PreserveContentDisposition)
+	a.Equal("attachment", transferInfo.SrcHTTPHeaders.ContentDisposition)
+	a.Equal("attachment", transferInfo.resolveContentDisposition("attachment"))
+
+	// unless one is given
+	order.BlobAttributes.ContentDisposition = "inline"
+	transferInfo = info(order)
+	a.Equal("inline", transferInfo.SrcHTTPHeaders.ContentDisposition)
+	a.Equal("inline", transferInfo.resolveContentDisposition("attachment"))
+
+	// or it isn't preserved
+	order.BlobAttributes = common.BlobTransferAttributes{}
+	transferInfo = info(order)
+	a.Equal("", transferInfo.SrcHTTPHeaders.ContentDisposition)
+	a.Equal("", transferInfo.resolveContentDisposition("attachment"))
+}
+
+func TestWithSourceLastModified(t *testing.T) {
+	a := assert.New(t)
+	lastModified := time.Date(2024, 3, 1, 12, 30, 0, 500, time.FixedZone("UTC+2", 2*60*60))
+
+	metadata := common.Metadata{"owner": to.Ptr("team")}
+	withTime := withSourceLastModified(metadata, lastModified)
+	a.Equal("2024-03-01T10:30:00.0000005Z", *withTime[common.SourceLastModifiedMetadataKey])
+	a.Equal("team", *withTime["owner"])
+	// the original is left alone
+	a.Len(metadata, 1)
+
+	a.Len(withSourceLastModified(nil, lastModified), 1)
+}
+
+func TestCreatePersistsBlobTypeOverrides(t *testing.T) {
+	a := assert.New(t)
+
+	mmf := createTestPlan(t, common.CopyJobPartOrderRequest{BlobAttributes: common.BlobTransferAttributes{
+		BlobTypeOverridesByExtension: map[string]common.BlobType{"VHD": common.EBlobType.PageBlob()},
+	}})
+	dstData := mmf.Plan().DstBlobData
+	overrides, err := common.ParseBlobTypeOverrides(string(dstData.BlobTypeOverrides[:dstData.BlobTypeOverridesLength]))
+	a.NoError(err)
+	a.Equal(map[string]common.BlobType{".vhd": common.EBlobType.PageBlob()}, overrides)
+
+	jpm := &jobPartMgr{blobTypeOverride: common.EBlobType.BlockBlob(), blobTypeOverridesByExtension: overrides}
+	a.Equal(common.EBlobType.PageBlob(), jpm.BlobTypeOverride("/tmp/source/disk.vhd"))
+	a.Equal(common.EBlobType.BlockBlob(), jpm.BlobTypeOverride("/tmp/source/file.txt"))
+
+	mmf = createTestPlan(t, common.CopyJobPartOrderRequest{})
+	a.Zero(mmf.Plan().DstBlobData.BlobTypeOverridesLength)
+}
@@ -154,6 +154,10 @@ func (jm *jobMgr) handleStatusUpdateMessage() {
 				}
 				js.TransfersCompleted++
 				js.TotalBytesTransferred += msg.TransferSize
+				js.BytesCompleted += int64(msg.TransferSize)
+				if msg.SourceDeleted {
+					js.TotalNumberOfSourceDeletions++
+				}
 			case common.ETransferStatus.Failed(),
 				common.ETransferStatus.TierAvailabilityCheckFailure(),
 				common.ETransferStatus.BlobTierFailure():
@@ -161,13 +165,19 @@ func (jm *jobMgr) handleStatusUpdateMessage() {
 					js.FoldersFailed++
 				}
 				js.TransfersFailed++
+				js.BytesFailed += int64(msg.TransferSize)
 				js.FailedTransfers = append(js.FailedTransfers, msg)
+				if jm.abortOnFirstError() {
+					js.AbortedOnFirstError = true
+				}
 			case common.ETransferStatus.SkippedEntityAlreadyExists(),
-				common.ETransferStatus.SkippedBlobHasSnapshots():
+				common.ETransferStatus.SkippedBlobHasSnapshots(),
+				common.ETransferStatus.SkippedReadDenied():
 				if msg.IsFolderProperties {
 					js.FoldersSkipped++
 				}
 				js.TransfersSkipped++
+				js.BytesSkipped += int64(msg.TransferSize)
 				js.SkippedTransfers = append(js.SkippedTransfers, msg)
 			}
 
@@ -0,0 +1,138 @@
+package ste
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+)
+
+func TestJobSummarySplitsFolderPropertyTransfers(t *testing.T) {
+	a := assert.New(t)
+	jm := &jobMgr{jstm: &jobStatusManager{
+		respChan:        make(chan common.ListJobSummaryResponse),
+		listReq:         make(chan struct{}),
+		partCreated:     make(chan JobPartCreatedMsg, 100),
+		xferDone:        make(chan xferDoneMsg, 1000),
+		xferDoneDrained: make(chan struct{}),
+		statusMgrDone:   make(chan struct{}),
+	}}
+	go jm.handleStatusUpdateMessage()
+
+	jm.SendJobPartCreatedMsg(JobPartCreatedMsg{TotalTransfers: 3, FileTransfers: 2, FolderTransfer: 1, IsFinalPart: true})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.Success()})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.Failed()})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.Success(), IsFolderProperties: true})
+
+	var js common.ListJobSummaryResponse
+	a.Eventually(func() bool {
+		js = jm.ListJobSummary()
+		return js.CompleteJobOrdered && js.TransfersCompleted+js.TransfersFailed == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the folders' property transfers are counted apart from the files, and both make up the total
+	a.Equal(uint32(2), js.FileTransfers)
+	a.Equal(uint32(1), js.FolderPropertyTransfers)
+	a.Equal(uint32(3), js.TotalTransfers)
+	a.Equal(uint32(1), js.FoldersCompleted)
+	a.Equal(uint32(2), js.TransfersCompleted)
+	a.Equal(uint32(0), js.FoldersFailed)
+	a.Equal(uint32(1), js.TransfersFailed)
+}
+
+func TestJobSummaryCountsReadDeniedAsSkipped(t *testing.T) {
+	a := assert.New(t)
+	jm := &jobMgr{jstm: &jobStatusManager{
+		respChan:        make(chan common.ListJobSummaryResponse),
+		listReq:         make(chan struct{}),
+		partCreated:     make(chan JobPartCreatedMsg, 100),
+		xferDone:        make(chan xferDoneMsg, 1000),
+		xferDoneDrained: make(chan struct{}),
+		statusMgrDone:   make(chan struct{}),
+	}}
+	go jm.handleStatusUpdateMessage()
+
+	jm.SendJobPartCreatedMsg(JobPartCreatedMsg{TotalTransfers: 3, FileTransfers: 3, IsFinalPart: true})
+	jm.SendXferDoneMsg(xferDoneMsg{Src: "/src/ok", TransferStatus: common.ETransferStatus.Success()})
+	jm.SendXferDoneMsg(xferDoneMsg{Src: "/src/denied", TransferStatus: common.ETransferStatus.SkippedReadDenied()})
+	jm.SendXferDoneMsg(xferDoneMsg{Src: "/src/exists", TransferStatus: common.ETransferStatus.SkippedEntityAlreadyExists()})
+
+	var js common.ListJobSummaryResponse
+	var skipped []common.TransferDetail
+	a.Eventually(func() bool {
+		js = jm.ListJobSummary()
+		// the lists are reset after each listing, so collect them
+		skipped = append(skipped, js.SkippedTransfers...)
+		return js.CompleteJobOrdered && js.TransfersCompleted+js.TransfersFailed+js.TransfersSkipped == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// unreadable files are skipped, not failed, and stand apart from the other skips by their status
+	a.Equal(uint32(1), js.TransfersCompleted)
+	a.Equal(uint32(0), js.TransfersFailed)
+	a.Equal(uint32(2), js.TransfersSkipped)
+	a.Len(skipped, 2)
+	a.Contains(skipped, common.TransferDetail{Src: "/src/denied", TransferStatus: common.ETransferStatus.SkippedReadDenied()})
+}
+
+func TestJobSummarySplitsBytesByStatus(t *testing.T) {
+	a := assert.New(t)
+	jm := &jobMgr{jstm: &jobStatusManager{
+		respChan:        make(chan common.ListJobSummaryResponse),
+		listReq:         make(chan struct{}),
+		partCreated:     make(chan JobPartCreatedMsg, 100),
+		xferDone:        make(chan xferDoneMsg, 1000),
+		xferDoneDrained: make(chan struct{}),
+		statusMgrDone:   make(chan struct{}),
+	}}
+	go jm.handleStatusUpdateMessage()
+
+	jm.SendJobPartCreatedMsg(JobPartCreatedMsg{TotalTransfers: 5, FileTransfers: 5, IsFinalPart: true, TotalBytesEnumerated: 1111100})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.Success(), TransferSize: 100})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.Success(), TransferSize: 1000})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.Failed(), TransferSize: 10000})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.SkippedEntityAlreadyExists(), TransferSize: 100000})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.SkippedReadDenied(), TransferSize: 1000000})
+
+	var js common.ListJobSummaryResponse
+	a.Eventually(func() bool {
+		js = jm.ListJobSummary()
+		return js.CompleteJobOrdered && js.TransfersCompleted+js.TransfersFailed+js.TransfersSkipped == 5
+	}, 5*time.Second, 10*time.Millisecond)
+
+	a.Equal(int64(1100), js.BytesCompleted)
+	a.Equal(int64(10000), js.BytesFailed)
+	a.Equal(int64(1100000), js.BytesSkipped)
+	// once every transfer is done, the splits account for every byte enumerated
+	a.Equal(js.TotalBytesEnumerated, uint64(js.BytesCompleted+js.BytesFailed+js.BytesSkipped))
+	a.Equal(js.TotalBytesTransferred, uint64(js.BytesCompleted))
+}
+
+func TestJobSummaryCountsSourceDeletions(t *testing.T) {
+	a := assert.New(t)
+	jm := &jobMgr{jstm: &jobStatusManager{
+		respChan:        make(chan common.ListJobSummaryResponse),
+		listReq:         make(chan struct{}),
+		partCreated:     make(chan JobPartCreatedMsg, 100),
+		xferDone:        make(chan xferDoneMsg, 1000),
+		xferDoneDrained: make(chan struct{}),
+		statusMgrDone:   make(chan struct{}),
+	}}
+	go jm.handleStatusUpdateMessage()
+
+	jm.SendJobPartCreatedMsg(JobPartCreatedMsg{TotalTransfers: 3, FileTransfers: 3, IsFinalPart: true})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.Success(), SourceDeleted: true})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.Success(), SourceDeleted: true})
+	jm.SendXferDoneMsg(xferDoneMsg{TransferStatus: common.ETransferStatus.Failed()})
+
+	var js common.ListJobSummaryResponse
+	a.Eventually(func() bool {
+		js = jm.ListJobSummary()
+		return js.CompleteJobOrdered && js.TransfersCompleted+js.TransfersFailed == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// only the moved sources are counted, the failed transfer's stays
+	a.Equal(uint32(2), js.TransfersCompleted)
+	a.Equal(uint32(2), js.TotalNumberOfSourceDeletions)
+}
@@ -87,6 +87,9 @@ type IJobMgr interface {
 	CancelPauseJobOrder(desiredJobStatus common.JobStatus) common.CancelPauseResumeResponse
 	IsDaemon() bool
 	GetJobErrorHandler() common.JobErrorHandler
+	SetFailFast(failFast bool)
+	SetCompletionWebhook(url, secret string)
+	TakeCompletionWebhook() (url, secret string, ok bool)
 
 	// Cleanup Functions
 	DeferredCleanupJobMgr()
@@ -276,13 +279,17 @@ type jobMgr struct {
 	atomicFinalPartOrderedIndicator int32
 	atomicTransferDirection         common.TransferDirection
 	atomicTotalFilesProcessed       int64 // Number of files processed across multiple job parts
-	concurrency                     ConcurrencySettings
-	logger                          common.ILoggerResetable
-	chunkStatusLogger               common.ChunkStatusLoggerCloser
-	jobID                           common.JobID // The Job's unique ID
-	ctx                             context.Context
-	cancel                          context.CancelFunc
-	pipelineNetworkStats            *PipelineNetworkStats
+	// atomicFailFast is set when the job should be aborted on the first failed transfer,
+	// and atomicAbortedOnFirstError once that has happened (so the cancellation is reported as a failure).
+	atomicFailFast            int32
+	atomicAbortedOnFirstError int32
+	concurrency               ConcurrencySettings
+	logger                    common.ILoggerResetable
+	chunkStatusLogger         common.ChunkStatusLoggerCloser
+	jobID                     common.JobID // The Job's unique ID
+	ctx                       context.Context
+	cancel                    context.CancelFunc
+	pipelineNetworkStats      *PipelineNetworkStats
 
 	// Share the same HTTP Client across all job parts, so that the we maximize reuse of
 	// its internal connection pool
@@ -316,6 +323,7 @@ type jobMgr struct {
 	fileCountLimiter    common.CacheLimiter
 	jstm                *jobStatusManager
 	jobErrorHandler     common.JobErrorHandler
+	completionWebhook   atomic.Pointer[completionWebhook]
 
 	isDaemon bool /* is it running as service */
 }
@@ -694,9 +702,17 @@ func (jm *jobMgr) reportJobPartDoneHandler() {
 
 				switch part0Plan.JobStatus() {
 				case common.EJobStatus.Cancelling():
-					part0Plan.SetJobStatus(common.EJobStatus.Cancelled())
-					if shouldLog {
-						jm.Log(common.LogInfo, fmt.Sprintf("%s %v successfully cancelled", partDescription, jm.jobID))
+					if atomic.LoadInt32(&jm.atomicAbortedOnFirstError) == 1 {
+						// we cancelled ourselves because of a failure, so don't report this as a user cancellation
+						part0Plan.SetJobStatus(common.EJobStatus.Failed())
+						if shouldLog {
+							jm.Log(common.LogInfo, fmt.Sprintf("%s %v aborted after the first failed transfer", partDescription, jm.jobID))
+						}
+					} else {
+						part0Plan.SetJobStatus(common.EJobStatus.Cancelled())
+						if shouldLog {
+							jm.Log(common.LogInfo, fmt.Sprintf("%s %v successfully cancelled", partDescription, jm.jobID))
+						}
 					}
 				case common.EJobStatus.InProgress():
 					part0Plan.SetJobStatus((common.EJobStatus).EnhanceJobStatusInfo(jobProgressInfo.transfersSkipped > 0,
@@ -1157,6 +1173,43 @@ func (jm *jobMgr) GetJobErrorHandler() common.JobErrorHandler {
 	return jm.jobErrorHandler
 }
 
+func (jm *jobMgr) SetFailFast(failFast bool) {
+	atomic.StoreInt32(&jm.atomicFailFast, common.Iff[int32](failFast, 1, 0))
+}
+
+type completionWebhook struct {
+	url, secret string
+	taken       atomic.Bool
+}
+
+func (jm *jobMgr) SetCompletionWebhook(url, secret string) {
+	// every part carries the webhook, but it must only be set once, or it could be notified again
+	jm.completionWebhook.CompareAndSwap(nil, &completionWebhook{url: url, secret: secret})
+}
+
+// TakeCompletionWebhook returns the webhook to notify of the job's completion. It's only returned once.
+func (jm *jobMgr) TakeCompletionWebhook() (url, secret string, ok bool) {
+	hook := jm.completionWebhook.Load()
+	if hook == nil || !hook.taken.CompareAndSwap(false, true) {
+		return "", "", false
+	}
+	return hook.url, hook.secret, true
+}
+
+// abortOnFirstError cancels the job after the first failed transfer, when FailFast was requested.
+// It returns true the first time it's called for a fail fast job, i.e. when the abort was actually initiated.
+func (jm *jobMgr) abortOnFirstError() bool {
+	if atomic.LoadInt32(&jm.atomicFailFast) == 0 ||
+		!atomic.CompareAndSwapInt32(&jm.atomicAbortedOnFirstError, 0, 1) {
+		return false
+	}
+
+	jm.Log(common.LogError, "A transfer failed and fail fast was requested. Cancelling the remaining transfers.")
+	// Cancel asynchronously, since we're called from the status manager, which must keep draining transfer messages
+	go jm.CancelPauseJobOrder(common.EJobStatus.Cancelling())
+	return true
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type jobPartToJobPartMgr struct {
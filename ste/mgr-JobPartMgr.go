@@ -32,10 +32,15 @@ type IJobPartMgr interface {
 	ReportTransferDone(status common.TransferStatus) uint32
 	GetOverwriteOption() common.OverwriteOption
 	GetForceIfReadOnly() bool
+	GetOverwriteMtimeToleranceSeconds() uint32
+	ErrorOnReadDenied() bool
+	OverwriteCheckMode() common.OverwriteCheckMode
 	AutoDecompress() bool
 	ScheduleChunks(chunkFunc chunkFunc)
 	RescheduleTransfer(jptm IJobPartTransferMgr)
-	BlobTypeOverride() common.BlobType
+	BlobTypeOverride(name string) common.BlobType
+	LineEndingMode(name string) common.LineEndingMode
+	MoveSource() bool
 	BlobTiers() (blockBlobTier common.BlockBlobTier, pageBlobTier common.PageBlobTier)
 	ShouldPutMd5() bool
 	DeleteDestinationFileIfNecessary() bool
@@ -102,9 +107,9 @@ func NewAzcopyHTTPClient(maxIdleConns int) *http.Client {
 func NewClientOptions(retry policy.RetryOptions, telemetry policy.TelemetryOptions, transport policy.Transporter, log LogOptions, srcCred *common.ScopedToken, dstCred *common.ScopedAuthenticator) azcore.ClientOptions {
 	// Pipeline will look like
 	// [includeResponsePolicy, newAPIVersionPolicy (ignored), NewTelemetryPolicy, perCall, NewRetryPolicy, perRetry, NewLogPolicy, httpHeaderPolicy, bodyDownloadPolicy]
-	perCallPolicies := []policy.Policy{azruntime.NewRequestIDPolicy(), NewRequestPriorityPolicy(), NewVersionPolicy(), newFileUploadRangeFromURLFixPolicy()}
+	perCallPolicies := []policy.Policy{azruntime.NewRequestIDPolicy(), NewRequestPriorityPolicy(), NewVersionPolicy(), newUserAgentSuffixPolicy(), newFileUploadRangeFromURLFixPolicy()}
 	// TODO : Default logging policy is not equivalent to old one. tracing HTTP request
-	perRetryPolicies := []policy.Policy{newRetryNotificationPolicy(), newLogPolicy(log), newStatsPolicy()}
+	perRetryPolicies := []policy.Policy{newTransferRetryBudgetPolicy(), newRetryNotificationPolicy(), newLogPolicy(log), newStatsPolicy()}
 	if dstCred != nil {
 		perCallPolicies = append(perRetryPolicies, NewDestReauthPolicy(dstCred))
 	}
@@ -181,6 +186,8 @@ type jobPartMgr struct {
 
 	blobTypeOverride common.BlobType // User specified blob type
 
+	blobTypeOverridesByExtension map[string]common.BlobType // User specified blob types, by file extension
+
 	preserveLastModifiedTime bool
 
 	newJobXfer newJobXfer // Method used to start the transfer
@@ -253,6 +260,11 @@ func (jpm *jobPartMgr) ScheduleTransfers(jobCtx context.Context) {
 		CacheControl:       string(dstData.CacheControl[:dstData.CacheControlLength]),
 	}
 
+	// compressed uploads are labelled as such
+	if dstData.CompressionAlgorithm != common.ECompressionAlgorithm.None() {
+		jpm.httpHeaders.ContentEncoding = dstData.CompressionAlgorithm.ContentEncoding()
+	}
+
 	jpm.putMd5 = dstData.PutMd5
 	jpm.blockBlobTier = dstData.BlockBlobTier
 	jpm.pageBlobTier = dstData.PageBlobTier
@@ -285,16 +297,46 @@ func (jpm *jobPartMgr) ScheduleTransfers(jobCtx context.Context) {
 		IsSourceEncrypted: dstData.IsSourceEncrypted,
 	}
 
+	// Any custom headers ride on the job's context, for the destination client's custom headers policy to set them
+	customHeadersString := string(dstData.CustomHeaders[:dstData.CustomHeadersLength])
+	if len(customHeadersString) > 0 {
+		values, err := url.ParseQuery(customHeadersString)
+		if err != nil {
+			panic("sanity check: custom headers string should be valid at this point: " + customHeadersString)
+		}
+		customHeaders := http.Header{}
+		for name := range values {
+			customHeaders.Set(name, values.Get(name))
+		}
+		jobCtx = context.WithValue(jobCtx, CustomHeaders, customHeaders)
+	}
+
+	if plan.UserAgentSuffixLength > 0 {
+		jobCtx = context.WithValue(jobCtx, UserAgentSuffix, string(plan.UserAgentSuffix[:plan.UserAgentSuffixLength]))
+	}
+
 	jpm.SetPropertiesFlags = dstData.SetPropertiesFlags
 	jpm.RehydratePriority = plan.RehydratePriority
 
 	jpm.preserveLastModifiedTime = plan.DstLocalData.PreserveLastModifiedTime
 
 	jpm.blobTypeOverride = plan.DstBlobData.BlobType
-	jpm.newJobXfer = computeJobXfer(plan.FromTo, plan.DstBlobData.BlobType)
+	blobTypeOverridesString := string(dstData.BlobTypeOverrides[:dstData.BlobTypeOverridesLength])
+	if len(blobTypeOverridesString) > 0 {
+		var err error
+		jpm.blobTypeOverridesByExtension, err = common.ParseBlobTypeOverrides(blobTypeOverridesString)
+		if err != nil {
+			panic("sanity check: blob type overrides string should be valid at this point: " + blobTypeOverridesString)
+		}
+	}
+	jpm.newJobXfer = computeJobXfer(plan.FromTo, plan.DstBlobData.BlobType, plan.MetadataOnly)
 
 	jpm.priority = plan.Priority
 
+	if plan.CheckpointIntervalSeconds > 0 {
+		go jpm.checkpointPeriodically(jobCtx, time.Duration(plan.CheckpointIntervalSeconds)*time.Second)
+	}
+
 	// *** Schedule this job part's transfers ***
 	for t := uint32(0); t < plan.NumTransfers; t++ {
 		jppt := plan.Transfer(t)
@@ -332,6 +374,9 @@ func (jpm *jobPartMgr) ScheduleTransfers(jobCtx context.Context) {
 		transferCtx, transferCancel := context.WithCancel(jobCtx)
 		// Add the pipeline network stats to the context. This will be manually unset for all sourceInfoProvider contexts.
 		transferCtx = withPipelineNetworkStats(transferCtx, jpm.jobMgr.PipelineNetworkStats())
+		if plan.MaxPerTransferRetries > 0 {
+			transferCtx = withTransferRetryBudget(transferCtx, plan.MaxPerTransferRetries)
+		}
 		// Initialize a job part transfer manager
 		jptm := &jobPartTransferMgr{
 			jobPartMgr:          jpm,
@@ -430,6 +475,18 @@ func (jpm *jobPartMgr) GetForceIfReadOnly() bool {
 	return jpm.Plan().ForceIfReadOnly
 }
 
+func (jpm *jobPartMgr) GetOverwriteMtimeToleranceSeconds() uint32 {
+	return jpm.Plan().OverwriteMtimeToleranceSeconds
+}
+
+func (jpm *jobPartMgr) ErrorOnReadDenied() bool {
+	return jpm.Plan().ErrorOnReadDenied
+}
+
+func (jpm *jobPartMgr) OverwriteCheckMode() common.OverwriteCheckMode {
+	return jpm.Plan().OverwriteCheckMode
+}
+
 func (jpm *jobPartMgr) AutoDecompress() bool {
 	return jpm.Plan().AutoDecompress
 }
@@ -493,8 +550,24 @@ func (jpm *jobPartMgr) inferContentType(fullFilePath string, dataFileToXfer []by
 	return strings.Split(http.DetectContentType(dataFileToXfer), ";")[0]
 }
 
-func (jpm *jobPartMgr) BlobTypeOverride() common.BlobType {
-	return jpm.blobTypeOverride
+// BlobTypeOverride returns the blob type the user asked for name to be transferred as, going by its extension first.
+func (jpm *jobPartMgr) BlobTypeOverride(name string) common.BlobType {
+	return common.BlobTypeForName(jpm.blobTypeOverridesByExtension, name, jpm.blobTypeOverride)
+}
+
+// LineEndingMode returns how the line endings of name are translated as it's uploaded, going by its extension.
+func (jpm *jobPartMgr) LineEndingMode(name string) common.LineEndingMode {
+	plan := jpm.Plan()
+	if plan.LineEndingMode == common.ELineEndingMode.None() ||
+		!common.HasTextExtension(common.ParseTextExtensions(string(plan.TextExtensions[:plan.TextExtensionsLength])), name) {
+		return common.ELineEndingMode.None()
+	}
+	return plan.LineEndingMode
+}
+
+// MoveSource returns whether the sources are deleted once they've been copied successfully.
+func (jpm *jobPartMgr) MoveSource() bool {
+	return jpm.Plan().MoveSource
 }
 
 func (jpm *jobPartMgr) BlobTiers() (blockBlobTier common.BlockBlobTier, pageBlobTier common.PageBlobTier) {
@@ -566,7 +639,7 @@ func (jpm *jobPartMgr) updateJobPartProgress(status common.TransferStatus) {
 		atomic.AddUint32(&jpm.atomicTransfersCompleted, 1)
 	case common.ETransferStatus.Failed(), common.ETransferStatus.BlobTierFailure():
 		atomic.AddUint32(&jpm.atomicTransfersFailed, 1)
-	case common.ETransferStatus.SkippedEntityAlreadyExists(), common.ETransferStatus.SkippedBlobHasSnapshots():
+	case common.ETransferStatus.SkippedEntityAlreadyExists(), common.ETransferStatus.SkippedBlobHasSnapshots(), common.ETransferStatus.SkippedReadDenied():
 		atomic.AddUint32(&jpm.atomicTransfersSkipped, 1)
 	case common.ETransferStatus.Restarted(): // When a job is resumed, number of failed should reset to 0
 		atomic.StoreUint32(&jpm.atomicTransfersFailed, 0)
@@ -576,6 +649,27 @@ func (jpm *jobPartMgr) updateJobPartProgress(status common.TransferStatus) {
 	}
 }
 
+// checkpointPeriodically flushes the plan file to disk every interval, so that the transfers' progress survives a crash,
+// until the job is cancelled or the part is closed.
+func (jpm *jobPartMgr) checkpointPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mapped, err := jpm.planMMF.Flush()
+			if !mapped {
+				return
+			}
+			if err != nil {
+				jpm.Log(common.LogWarning, fmt.Sprintf("failed to checkpoint the job part plan: %v", err))
+			}
+		}
+	}
+}
+
 // Call Done when a transfer has completed its epilog; this method returns the number of transfers completed so far
 func (jpm *jobPartMgr) ReportTransferDone(status common.TransferStatus) (transfersDone uint32) {
 	transfersDone = atomic.AddUint32(&jpm.atomicTransfersDone, 1)
@@ -29,6 +29,9 @@ type IJobPartTransferMgr interface {
 	DeleteDestinationFileIfNecessary() bool
 	MD5ValidationOption() common.HashValidationOption
 	BlobTypeOverride() common.BlobType
+	LineEndingMode() common.LineEndingMode
+	MoveSource() bool
+	ReportSourceDeleted()
 	BlobTiers() (blockBlobTier common.BlockBlobTier, pageBlobTier common.PageBlobTier)
 	JobHasLowFileCount() bool
 	// ScheduleChunk(chunkFunc chunkFunc)
@@ -41,6 +44,9 @@ type IJobPartTransferMgr interface {
 	StartJobXfer()
 	GetOverwriteOption() common.OverwriteOption
 	GetForceIfReadOnly() bool
+	GetOverwriteMtimeToleranceSeconds() uint32
+	ErrorOnReadDenied() bool
+	ChecksDestination() bool
 	ShouldDecompress() bool
 	GetSourceCompressionType() (common.CompressionType, error)
 	ReportChunkDone(id common.ChunkID) (lastChunk bool, chunksDone uint32)
@@ -135,6 +141,17 @@ type TransferInfo struct {
 	S2SSourceChangeValidation      bool
 	DestLengthValidation           bool
 	S2SInvalidMetadataHandleOption common.InvalidMetadataHandleOption
+	RequesterPays                  bool // S3 only
+	PreserveTags                   bool // S3 only
+	PreserveSourceTimeAsMetadata   bool
+	MetadataKeyCasePolicy          common.MetadataKeyCasePolicy // S3 only
+	HTTPVersionPreference          common.HTTPVersionPreference // S3 only
+	ContentDisposition             string                       // set in place of the source's, when not empty
+	SourceRegion                   string                       // S3 only, used when the source's URL names no region
+	PreserveContentDisposition     bool
+
+	// Upload
+	CompressionAlgorithm common.CompressionAlgorithm
 
 	// Blob
 	SrcBlobType    blob.BlobType   // used for both S2S and for downloads to local from blob
@@ -181,6 +198,11 @@ func (i *TransferInfo) entityTypeLogIndicator() string {
 	}
 }
 
+// resolveContentDisposition returns the content disposition the destination gets, given the source's.
+func (i *TransferInfo) resolveContentDisposition(source string) string {
+	return common.ResolveContentDisposition(i.ContentDisposition, i.PreserveContentDisposition, source)
+}
+
 type SrcProperties struct {
 	SrcHTTPHeaders common.ResourceHTTPHeaders // User for S2S copy, where per transfer's src properties need be set in destination.
 	SrcMetadata    common.Metadata
@@ -211,6 +233,9 @@ type jobPartTransferMgr struct {
 	// used to show whether THIS jptm holds the destination lock
 	atomicDestLockHeldIndicator uint32
 
+	// used to show whether the source was deleted after the copy, for moves
+	atomicSourceDeletedIndicator uint32
+
 	jobPartMgr          IJobPartMgr // Refers to the "owning" Job Part
 	jobPartPlanTransfer *JobPartPlanTransfer
 	transferIndex       uint32
@@ -257,6 +282,19 @@ func (jptm *jobPartTransferMgr) GetForceIfReadOnly() bool {
 	return jptm.jobPartMgr.GetForceIfReadOnly()
 }
 
+func (jptm *jobPartTransferMgr) GetOverwriteMtimeToleranceSeconds() uint32 {
+	return jptm.jobPartMgr.GetOverwriteMtimeToleranceSeconds()
+}
+
+func (jptm *jobPartTransferMgr) ErrorOnReadDenied() bool {
+	return jptm.jobPartMgr.ErrorOnReadDenied()
+}
+
+// ChecksDestination reports whether the destination's existence is to be checked before overwriting it
+func (jptm *jobPartTransferMgr) ChecksDestination() bool {
+	return jptm.jobPartMgr.OverwriteCheckMode().ChecksDestination(jptm.GetOverwriteOption())
+}
+
 func (jptm *jobPartTransferMgr) ShouldDecompress() bool {
 	if jptm.jobPartMgr.AutoDecompress() {
 		ct, _ := jptm.GetSourceCompressionType()
@@ -399,6 +437,15 @@ func (jptm *jobPartTransferMgr) Info() *TransferInfo {
 	}
 	putBlobSize = common.Iff(putBlobSize > common.MaxPutBlobSize, common.MaxPutBlobSize, putBlobSize)
 
+	// Backend fetched properties replace this metadata, so the source info providers that fetch them add the time again
+	if dstBlobData.PreserveSourceTimeAsMetadata && entityType == common.EEntityType.File() && jptm.jobPartPlanTransfer.ModifiedTime != 0 {
+		srcMetadata = withSourceLastModified(srcMetadata, jptm.LastModifiedTime())
+	}
+
+	// Explicit dispositions override the source's, which are only kept when asked to be
+	explicitDisposition := string(dstBlobData.ContentDisposition[:dstBlobData.ContentDispositionLength])
+	srcHTTPHeaders.ContentDisposition = common.ResolveContentDisposition(explicitDisposition, dstBlobData.PreserveContentDisposition, srcHTTPHeaders.ContentDisposition)
+
 	var srcBlobTags common.BlobTags
 	if blobTags != nil {
 		srcBlobTags = common.BlobTags{}
@@ -429,6 +476,15 @@ func (jptm *jobPartTransferMgr) Info() *TransferInfo {
 		S2SInvalidMetadataHandleOption: s2sInvalidMetadataHandleOption,
 		BlobFSRecursiveDelete:          plan.BlobFSRecursiveDelete,
 		DestLengthValidation:           DestLengthValidation,
+		RequesterPays:                  plan.RequesterPays,
+		HTTPVersionPreference:          plan.HTTPVersionPreference,
+		SourceRegion:                   string(plan.SourceRegion[:plan.SourceRegionLength]),
+		PreserveTags:                   dstBlobData.PreserveTags,
+		PreserveSourceTimeAsMetadata:   dstBlobData.PreserveSourceTimeAsMetadata,
+		MetadataKeyCasePolicy:          dstBlobData.MetadataKeyCasePolicy,
+		ContentDisposition:             explicitDisposition,
+		PreserveContentDisposition:     dstBlobData.PreserveContentDisposition,
+		CompressionAlgorithm:           dstBlobData.CompressionAlgorithm,
 		SrcProperties: SrcProperties{
 			SrcHTTPHeaders: srcHTTPHeaders,
 			SrcMetadata:    srcMetadata,
@@ -574,7 +630,20 @@ func (jptm *jobPartTransferMgr) PermanentDeleteOption() common.PermanentDeleteOp
 }
 
 func (jptm *jobPartTransferMgr) BlobTypeOverride() common.BlobType {
-	return jptm.jobPartMgr.BlobTypeOverride()
+	return jptm.jobPartMgr.BlobTypeOverride(jptm.Info().Source)
+}
+
+func (jptm *jobPartTransferMgr) LineEndingMode() common.LineEndingMode {
+	return jptm.jobPartMgr.LineEndingMode(jptm.Info().Source)
+}
+
+func (jptm *jobPartTransferMgr) MoveSource() bool {
+	return jptm.jobPartMgr.MoveSource()
+}
+
+// ReportSourceDeleted records that the source was deleted after being copied, so that the job's summary counts it
+func (jptm *jobPartTransferMgr) ReportSourceDeleted() {
+	atomic.StoreUint32(&jptm.atomicSourceDeletedIndicator, 1)
 }
 
 func (jptm *jobPartTransferMgr) BlobTiers() (blockBlobTier common.BlockBlobTier, pageBlobTier common.PageBlobTier) {
@@ -854,7 +923,13 @@ func (jptm *jobPartTransferMgr) failActiveTransfer(typ transferErrorCode, descri
 		fullMsg := fmt.Sprintf("%s. When %s. X-Ms-Request-Id: %s\n", msg, descriptionOfWhereErrorOccurred, requestID) // trailing \n to separate it better from any later, unrelated, log lines
 		jptm.logTransferError(typ, jptm.Info().Source, jptm.Info().Destination, fullMsg, status)
 		jptm.SetStatus(failureStatus)
-		jptm.SetErrorCode(int32(status)) // TODO: what are the rules about when this needs to be set, and doesn't need to be (e.g. for earlier failures)?
+		jptm.jobPartPlanTransfer.SetLastHTTPStatus(int32(status))
+		errorCode := int32(status)
+		var retriesExhausted *transferRetriesExhaustedError
+		if errors.As(err, &retriesExhausted) {
+			errorCode = common.TransferErrorCodeRetriesExhausted
+		}
+		jptm.SetErrorCode(errorCode) // TODO: what are the rules about when this needs to be set, and doesn't need to be (e.g. for earlier failures)?
 		// If the status code was 403, it means there was an authentication error and we exit.
 		// User can resume the job if completely ordered with a new sas.
 		if status == http.StatusForbidden &&
@@ -1007,6 +1082,8 @@ func (jptm *jobPartTransferMgr) ReportTransferDone() uint32 {
 		TransferStatus:     jptm.jobPartPlanTransfer.TransferStatus(),
 		TransferSize:       uint64(jptm.Info().SourceSize),
 		ErrorCode:          jptm.ErrorCode(),
+		LastHTTPStatus:     jptm.jobPartPlanTransfer.LastHTTPStatus(),
+		SourceDeleted:      atomic.LoadUint32(&jptm.atomicSourceDeletedIndicator) == 1,
 	})
 
 	return jptm.jobPartMgr.ReportTransferDone(jptm.jobPartPlanTransfer.TransferStatus())
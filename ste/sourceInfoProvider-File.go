@@ -314,13 +314,16 @@ func (p *fileSourceInfoProvider) Properties() (*SrcProperties, error) {
 				SrcHTTPHeaders: common.ResourceHTTPHeaders{
 					ContentType:        properties.ContentType(),
 					ContentEncoding:    properties.ContentEncoding(),
-					ContentDisposition: properties.ContentDisposition(),
+					ContentDisposition: p.transferInfo.resolveContentDisposition(properties.ContentDisposition()),
 					ContentLanguage:    properties.ContentLanguage(),
 					CacheControl:       properties.CacheControl(),
 					ContentMD5:         properties.ContentMD5(),
 				},
 				SrcMetadata: properties.Metadata(),
 			}
+			if p.transferInfo.PreserveSourceTimeAsMetadata {
+				srcProperties.SrcMetadata = withSourceLastModified(srcProperties.SrcMetadata, properties.LastModified())
+			}
 		case common.EEntityType.Folder():
 			srcProperties = &SrcProperties{
 				SrcHTTPHeaders: common.ResourceHTTPHeaders{}, // no contentType etc for folders
@@ -97,13 +97,16 @@ func (p *gcpSourceInfoProvider) Properties() (*SrcProperties, error) {
 			SrcHTTPHeaders: common.ResourceHTTPHeaders{
 				ContentType:        objectInfo.ContentType,
 				ContentEncoding:    oie.ContentEncoding(),
-				ContentDisposition: oie.ContentDisposition(),
+				ContentDisposition: p.transferInfo.resolveContentDisposition(oie.ContentDisposition()),
 				ContentLanguage:    oie.ContentLanguage(),
 				CacheControl:       oie.CacheControl(),
 				ContentMD5:         oie.ContentMD5(),
 			},
 			SrcMetadata: oie.NewCommonMetadata(),
 		}
+		if p.transferInfo.PreserveSourceTimeAsMetadata {
+			srcProperties.SrcMetadata = withSourceLastModified(srcProperties.SrcMetadata, objectInfo.Updated)
+		}
 	}
 	resolvedMetadata, err := p.handleInvalidMetadataKeys(srcProperties.SrcMetadata)
 
@@ -0,0 +1,120 @@
+// Copyright © 2025 Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ste
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+)
+
+// Source info provider for local files whose data is transformed as they're uploaded, e.g. compressed.
+// The chunks of a transfer are read at known offsets and sizes, so the file is transformed into a temporary file up front,
+// and that's what gets uploaded. Everything but the data itself (properties, last modified time, etc.) still comes from the original file.
+type transformedLocalSourceInfoProvider struct {
+	ILocalSourceInfoProvider
+	transformedPath string
+	transformedSize int64
+}
+
+func newCompressedLocalSourceInfoProvider(jptm IJobPartTransferMgr, original ILocalSourceInfoProvider, algorithm common.CompressionAlgorithm) (*transformedLocalSourceInfoProvider, error) {
+	if algorithm != common.ECompressionAlgorithm.Gzip() {
+		return nil, fmt.Errorf("unsupported compression algorithm %s", algorithm)
+	}
+	return newTransformedLocalSourceInfoProvider(jptm, original, "compress", func(dst io.Writer, src io.Reader) error {
+		w := gzip.NewWriter(dst)
+		_, err := io.Copy(w, src)
+		return errors.Join(err, w.Close())
+	})
+}
+
+func newLineEndingLocalSourceInfoProvider(jptm IJobPartTransferMgr, original ILocalSourceInfoProvider, mode common.LineEndingMode) (*transformedLocalSourceInfoProvider, error) {
+	return newTransformedLocalSourceInfoProvider(jptm, original, "translate the line endings of", mode.Translate)
+}
+
+// newTransformedLocalSourceInfoProvider writes the source, as transform has it, to a temporary file. The action describes
+// the transform in errors, e.g. "compress".
+func newTransformedLocalSourceInfoProvider(jptm IJobPartTransferMgr, original ILocalSourceInfoProvider, action string, transform func(dst io.Writer, src io.Reader) error) (*transformedLocalSourceInfoProvider, error) {
+	src, err := original.OpenSourceFile()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	transformed, err := os.CreateTemp("", "azcopy-transformed-*")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create a temporary file to %s the source: %w", action, err)
+	}
+	p := &transformedLocalSourceInfoProvider{ILocalSourceInfoProvider: original, transformedPath: transformed.Name()}
+
+	err = transform(transformed, io.NewSectionReader(src, 0, jptm.Info().SourceSize))
+	if err == nil {
+		var fi os.FileInfo
+		if fi, err = transformed.Stat(); err == nil {
+			p.transformedSize = fi.Size()
+		}
+	}
+	err = errors.Join(err, transformed.Close())
+	if err != nil {
+		p.Cleanup()
+		return nil, fmt.Errorf("couldn't %s the source: %w", action, err)
+	}
+
+	return p, nil
+}
+
+func (p *transformedLocalSourceInfoProvider) OpenSourceFile() (common.CloseableReaderAt, error) {
+	return os.Open(p.transformedPath)
+}
+
+// SourceSize returns the size of the transformed data, which is what's uploaded
+func (p *transformedLocalSourceInfoProvider) SourceSize() int64 {
+	return p.transformedSize
+}
+
+func (p *transformedLocalSourceInfoProvider) GetMD5(offset, count int64) ([]byte, error) {
+	transformed, err := p.OpenSourceFile()
+	if err != nil {
+		return nil, err
+	}
+	defer transformed.Close()
+	data := make([]byte, count)
+	size, err := transformed.ReadAt(data, offset)
+	if err != nil && !(errors.Is(err, io.EOF) && int64(size) == count) {
+		return nil, err
+	}
+	h := md5.New()
+	if _, err = io.Copy(h, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// Cleanup removes the transformed copy of the source
+func (p *transformedLocalSourceInfoProvider) Cleanup() {
+	_ = os.Remove(p.transformedPath)
+}
@@ -48,6 +48,10 @@ type s3SourceInfoProvider struct {
 // This value could be further tuned, or exposed to user for customization, according to user feedback.
 const defaultPresignExpires = time.Hour * 7 * 24
 
+// requesterPaysHeader acknowledges that the requester is charged for reading from a requester-pays bucket.
+// S3 accepts it as a query parameter too, which is how it's carried by presigned URLs.
+const requesterPaysHeader = "x-amz-request-payer"
+
 var s3ClientFactory = common.NewS3ClientFactory()
 
 func newS3SourceInfoProvider(jptm IJobPartTransferMgr) (ISourceInfoProvider, error) {
@@ -74,8 +78,10 @@ func newS3SourceInfoProvider(jptm IJobPartTransferMgr) (ISourceInfoProvider, err
 	p.s3Client, err = s3ClientFactory.GetS3Client(ctx, common.CredentialInfo{
 		CredentialType: p.credType,
 		S3CredentialInfo: common.S3CredentialInfo{
-			Endpoint: p.s3URLPart.Endpoint,
-			Region:   p.s3URLPart.Region,
+			Endpoint:    p.s3URLPart.Endpoint,
+			Region:      common.ResolveS3Region(p.s3URLPart, p.transferInfo.SourceRegion),
+			PathStyle:   p.s3URLPart.RequiresPathStyle(),
+			HTTPVersion: p.transferInfo.HTTPVersionPreference,
 		},
 	}, jptm)
 	if err != nil {
@@ -89,13 +95,26 @@ func (p *s3SourceInfoProvider) PreSignedSourceURL() (string, error) {
 	if p.credType == common.ECredentialType.S3PublicBucket() {
 		return p.rawSourceURL.String(), nil
 	}
-	source, err := p.s3Client.PresignedGetObject(p.s3URLPart.BucketName, p.s3URLPart.ObjectKey, defaultPresignExpires, url.Values{})
+	reqParams := url.Values{}
+	if p.transferInfo.RequesterPays {
+		reqParams.Set(requesterPaysHeader, "requester")
+	}
+	source, err := p.s3Client.PresignedGetObject(p.s3URLPart.BucketName, p.s3URLPart.ObjectKey, defaultPresignExpires, reqParams)
 	if err != nil {
 		return "", err
 	}
 	return source.String(), nil
 }
 
+// getObjectOptions returns the options every read of the source object must carry.
+func (p *s3SourceInfoProvider) getObjectOptions() minio.GetObjectOptions {
+	options := minio.GetObjectOptions{}
+	if p.transferInfo.RequesterPays {
+		options.Set(requesterPaysHeader, "requester")
+	}
+	return options
+}
+
 func (p *s3SourceInfoProvider) Properties() (*SrcProperties, error) {
 	srcProperties := SrcProperties{
 		SrcHTTPHeaders: p.transferInfo.SrcHTTPHeaders,
@@ -105,7 +124,7 @@ func (p *s3SourceInfoProvider) Properties() (*SrcProperties, error) {
 
 	// Get properties in backend.
 	if p.transferInfo.S2SGetPropertiesInBackend {
-		objectInfo, err := p.s3Client.StatObject(p.s3URLPart.BucketName, p.s3URLPart.ObjectKey, minio.StatObjectOptions{})
+		objectInfo, err := p.s3Client.StatObject(p.s3URLPart.BucketName, p.s3URLPart.ObjectKey, minio.StatObjectOptions{GetObjectOptions: p.getObjectOptions()})
 		if err != nil {
 			return nil, err
 		}
@@ -115,13 +134,16 @@ func (p *s3SourceInfoProvider) Properties() (*SrcProperties, error) {
 			SrcHTTPHeaders: common.ResourceHTTPHeaders{
 				ContentType:        objectInfo.ContentType,
 				ContentEncoding:    oie.ContentEncoding(),
-				ContentDisposition: oie.ContentDisposition(),
+				ContentDisposition: p.transferInfo.resolveContentDisposition(oie.ContentDisposition()),
 				ContentLanguage:    oie.ContentLanguage(),
 				CacheControl:       oie.CacheControl(),
 				ContentMD5:         oie.ContentMD5(),
 			},
 			SrcMetadata: oie.NewCommonMetadata(),
 		}
+		if p.transferInfo.PreserveSourceTimeAsMetadata {
+			srcProperties.SrcMetadata = withSourceLastModified(srcProperties.SrcMetadata, objectInfo.LastModified)
+		}
 	}
 
 	// Handle invalid metadata.
@@ -130,11 +152,31 @@ func (p *s3SourceInfoProvider) Properties() (*SrcProperties, error) {
 	if err != nil {
 		return nil, err
 	}
-	srcProperties.SrcMetadata = resolvedMetadata
+	// S3's metadata keys are case-sensitive, Azure's aren't
+	srcProperties.SrcMetadata, err = resolvedMetadata.ApplyKeyCasePolicy(p.transferInfo.MetadataKeyCasePolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tags given for the job win over the object's own
+	_, _, jobTags, _ := p.jptm.ResourceDstData(nil)
+	if len(jobTags) > 0 {
+		srcProperties.SrcBlobTags = jobTags
+	} else if p.transferInfo.PreserveTags {
+		srcProperties.SrcBlobTags, err = p.getObjectTags()
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &srcProperties, nil
 }
 
+// getObjectTags reads the source object's tags.
+func (p *s3SourceInfoProvider) getObjectTags() (common.BlobTags, error) {
+	return common.GetS3ObjectTags(p.jptm.Context(), p.s3Client, p.s3URLPart, p.transferInfo.RequesterPays, p.credType == common.ECredentialType.S3PublicBucket())
+}
+
 // handleInvalidMetadataKeys handles invalid metadata for S3 source.
 func (p *s3SourceInfoProvider) handleInvalidMetadataKeys(m common.Metadata) (common.Metadata, error) {
 	if m == nil {
@@ -177,7 +219,7 @@ func (p *s3SourceInfoProvider) IsLocal() bool {
 }
 
 func (p *s3SourceInfoProvider) GetFreshFileLastModifiedTime() (time.Time, error) {
-	objectInfo, err := p.s3Client.StatObject(p.s3URLPart.BucketName, p.s3URLPart.ObjectKey, minio.StatObjectOptions{})
+	objectInfo, err := p.s3Client.StatObject(p.s3URLPart.BucketName, p.s3URLPart.ObjectKey, minio.StatObjectOptions{GetObjectOptions: p.getObjectOptions()})
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -189,7 +231,7 @@ func (p *s3SourceInfoProvider) EntityType() common.EntityType {
 }
 
 func (p *s3SourceInfoProvider) GetMD5(offset, count int64) ([]byte, error) {
-	options := minio.GetObjectOptions{}
+	options := p.getObjectOptions()
 	r := formatHTTPRange(offset, count)
 	if r != nil {
 		options.Set("Range", *r)
@@ -26,6 +26,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azfile/file"
 
@@ -132,6 +133,14 @@ type ICustomLocalOpener interface {
 
 type sourceInfoProviderFactory func(jptm IJobPartTransferMgr) (ISourceInfoProvider, error)
 
+// withSourceLastModified returns a copy of metadata that records the source's last modified time,
+// as the jobs that preserve the source's time as metadata need.
+func withSourceLastModified(metadata common.Metadata, lastModified time.Time) common.Metadata {
+	withTime := metadata.Clone()
+	withTime[common.SourceLastModifiedMetadataKey] = to.Ptr(lastModified.UTC().Format(time.RFC3339Nano))
+	return withTime
+}
+
 // ///////////////////////////////////////////////////////////////////////////////////////////////
 // Default copy remote source info provider which provides info sourced from transferInfo.
 // It implements all methods of ISourceInfoProvider except for GetFreshLastModifiedTime.
@@ -140,6 +140,18 @@ func (t *testJobPartTransferManager) BlobTypeOverride() common.BlobType {
 	panic("implement me")
 }
 
+func (t *testJobPartTransferManager) LineEndingMode() common.LineEndingMode {
+	panic("implement me")
+}
+
+func (t *testJobPartTransferManager) MoveSource() bool {
+	panic("implement me")
+}
+
+func (t *testJobPartTransferManager) ReportSourceDeleted() {
+	panic("implement me")
+}
+
 func (t *testJobPartTransferManager) BlobTiers() (blockBlobTier common.BlockBlobTier, pageBlobTier common.PageBlobTier) {
 	panic("implement me")
 }
@@ -184,6 +196,18 @@ func (t *testJobPartTransferManager) GetForceIfReadOnly() bool {
 	panic("implement me")
 }
 
+func (t *testJobPartTransferManager) GetOverwriteMtimeToleranceSeconds() uint32 {
+	panic("implement me")
+}
+
+func (t *testJobPartTransferManager) ErrorOnReadDenied() bool {
+	panic("implement me")
+}
+
+func (t *testJobPartTransferManager) ChecksDestination() bool {
+	panic("implement me")
+}
+
 func (t *testJobPartTransferManager) ShouldDecompress() bool {
 	panic("implement me")
 }
@@ -26,6 +26,7 @@ import (
 	"errors"
 	"fmt"
 	"hash"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"runtime"
@@ -217,6 +218,7 @@ func anyToRemote_file(jptm IJobPartTransferMgr, info *TransferInfo, pacer pacer,
 	defer jptm.LogChunkStatus(pseudoId, common.EWaitReason.ChunkDone())
 
 	srcSize := info.SourceSize
+	chunksScheduled := false
 
 	// step 1. perform initial checks
 	if jptm.WasCanceled() {
@@ -248,6 +250,32 @@ func anyToRemote_file(jptm IJobPartTransferMgr, info *TransferInfo, pacer pacer,
 		panic("configuration error. Source Info Provider does not have File entity type")
 	}
 
+	// Compress a local source, or translate its line endings, up front if asked to. From here on, the transfer is of
+	// the transformed data, and its size. Validation keeps the two from being combined.
+	var transformed *transformedLocalSourceInfoProvider
+	if info.CompressionAlgorithm != common.ECompressionAlgorithm.None() && srcInfoProvider.IsLocal() {
+		transformed, err = newCompressedLocalSourceInfoProvider(jptm, srcInfoProvider.(ILocalSourceInfoProvider), info.CompressionAlgorithm)
+	} else if lineEndingMode := jptm.LineEndingMode(); lineEndingMode != common.ELineEndingMode.None() && srcInfoProvider.IsLocal() {
+		transformed, err = newLineEndingLocalSourceInfoProvider(jptm, srcInfoProvider.(ILocalSourceInfoProvider), lineEndingMode)
+	}
+	if err != nil {
+		jptm.LogSendError(info.Source, info.Destination, err.Error(), 0)
+		jptm.SetStatus(common.ETransferStatus.Failed())
+		jptm.ReportTransferDone()
+		return
+	}
+	if transformed != nil {
+		// the epilogue removes the transformed copy, but it only runs once chunks are scheduled
+		defer func() {
+			if !chunksScheduled {
+				transformed.Cleanup()
+			}
+		}()
+		srcInfoProvider = transformed
+		info.SourceSize = transformed.SourceSize()
+		srcSize = info.SourceSize
+	}
+
 	s, err := senderFactory(jptm, info.Destination, pacer, srcInfoProvider)
 	if err != nil {
 		jptm.LogSendError(info.Source, info.Destination, err.Error(), 0)
@@ -266,10 +294,10 @@ func anyToRemote_file(jptm IJobPartTransferMgr, info *TransferInfo, pacer pacer,
 	}
 
 	// step 3: check overwrite option
-	// if the force Write flags is set to false or prompt
+	// if the force Write flags is set to false or prompt (or the check mode asks for it regardless)
 	// then check the file exists at the remote location
 	// if it does, react accordingly
-	if jptm.GetOverwriteOption() != common.EOverwriteOption.True() {
+	if jptm.ChecksDestination() {
 		exists, dstLmt, existenceErr := s.RemoteFileExists()
 		if existenceErr != nil {
 			jptm.LogSendError(info.Source, info.Destination, "Could not check destination file existence. "+existenceErr.Error(), 0)
@@ -278,7 +306,7 @@ func anyToRemote_file(jptm IJobPartTransferMgr, info *TransferInfo, pacer pacer,
 			return
 		}
 		if exists {
-			shouldOverwrite := false
+			shouldOverwrite := jptm.GetOverwriteOption() == common.EOverwriteOption.True()
 
 			// if necessary, prompt to confirm user's intent
 			if jptm.GetOverwriteOption() == common.EOverwriteOption.Prompt() {
@@ -287,8 +315,8 @@ func anyToRemote_file(jptm IJobPartTransferMgr, info *TransferInfo, pacer pacer,
 				parsed.RawQuery = ""
 				shouldOverwrite = jptm.GetOverwritePrompter().ShouldOverwrite(parsed.String(), common.EEntityType.File())
 			} else if jptm.GetOverwriteOption() == common.EOverwriteOption.IfSourceNewer() {
-				// only overwrite if source lmt is newer (after) the destination
-				if jptm.LastModifiedTime().After(dstLmt) {
+				// only overwrite if source lmt is newer (after) the destination, beyond the tolerance
+				if common.IsSourceNewer(jptm.LastModifiedTime(), dstLmt, jptm.GetOverwriteMtimeToleranceSeconds()) {
 					shouldOverwrite = true
 				}
 			}
@@ -311,6 +339,13 @@ func anyToRemote_file(jptm IJobPartTransferMgr, info *TransferInfo, pacer pacer,
 	if srcInfoProvider.IsLocal() {
 		sourceFileFactory = srcInfoProvider.(ILocalSourceInfoProvider).OpenSourceFile // all local providers must implement this interface
 		srcFile, err = sourceFileFactory()
+		if err != nil && errors.Is(err, fs.ErrPermission) && !jptm.ErrorOnReadDenied() {
+			// a few unreadable files shouldn't fail the job, so they're skipped unless asked otherwise
+			jptm.LogAtLevelForCurrentTransfer(common.LogWarning, "Permission to read the source was denied, so it will be skipped. "+err.Error())
+			jptm.SetStatus(common.ETransferStatus.SkippedReadDenied())
+			jptm.ReportTransferDone()
+			return
+		}
 		if err != nil {
 			suffix := ""
 			if strings.Contains(err.Error(), "Access is denied") && runtime.GOOS == "windows" {
@@ -373,6 +408,7 @@ func anyToRemote_file(jptm IJobPartTransferMgr, info *TransferInfo, pacer pacer,
 	// step 5b: tell jptm what to expect, and how to clean up at the end
 	jptm.SetNumberOfChunks(numChunks)
 	jptm.SetActionAfterLastChunk(func() { epilogueWithCleanupSendToRemote(jptm, s, srcInfoProvider) })
+	chunksScheduled = true
 
 	// stop tracking pseudo id (since real chunk id's will be tracked from here on)
 	jptm.LogChunkStatus(pseudoId, common.EWaitReason.ChunkDone())
@@ -592,6 +628,19 @@ func epilogueWithCleanupSendToRemote(jptm IJobPartTransferMgr, s sender, sip ISo
 		s.Cleanup() // Perform jptm cleanup, if THIS jptm has the lock on the destination
 	}
 
+	if transformed, ok := sip.(*transformedLocalSourceInfoProvider); ok {
+		transformed.Cleanup()
+	}
+
+	if _, isS2SCopier := s.(s2sCopier); jptm.IsLive() && isS2SCopier && jptm.MoveSource() {
+		// the copy has been verified by now, so the source can go
+		if err := deleteMovedSource(jptm); err != nil {
+			jptm.FailActiveS2SCopy("Deleting the moved source", err)
+		} else {
+			jptm.ReportSourceDeleted()
+		}
+	}
+
 	commonSenderCompletion(jptm, s, info)
 }
 
@@ -78,10 +78,10 @@ func anyToRemote_symlink(jptm IJobPartTransferMgr, info *TransferInfo, pacer pac
 	}
 
 	// check overwrite option
-	// if the force Write flags is set to false or prompt
+	// if the force Write flags is set to false or prompt (or the check mode asks for it regardless)
 	// then check the file exists at the remote location
 	// if it does, react accordingly
-	if jptm.GetOverwriteOption() != common.EOverwriteOption.True() {
+	if jptm.ChecksDestination() {
 		exists, dstLmt, existenceErr := s.RemoteFileExists()
 		if existenceErr != nil {
 			jptm.LogSendError(info.Source, info.Destination, "Could not check destination file existence. "+existenceErr.Error(), 0)
@@ -90,7 +90,7 @@ func anyToRemote_symlink(jptm IJobPartTransferMgr, info *TransferInfo, pacer pac
 			return
 		}
 		if exists {
-			shouldOverwrite := false
+			shouldOverwrite := jptm.GetOverwriteOption() == common.EOverwriteOption.True()
 
 			// if necessary, prompt to confirm user's intent
 			if jptm.GetOverwriteOption() == common.EOverwriteOption.Prompt() {
@@ -99,8 +99,8 @@ func anyToRemote_symlink(jptm IJobPartTransferMgr, info *TransferInfo, pacer pac
 				parsed.RawQuery = ""
 				shouldOverwrite = jptm.GetOverwritePrompter().ShouldOverwrite(parsed.String(), common.EEntityType.File())
 			} else if jptm.GetOverwriteOption() == common.EOverwriteOption.IfSourceNewer() {
-				// only overwrite if source lmt is newer (after) the destination
-				if jptm.LastModifiedTime().After(dstLmt) {
+				// only overwrite if source lmt is newer (after) the destination, beyond the tolerance
+				if common.IsSourceNewer(jptm.LastModifiedTime(), dstLmt, jptm.GetOverwriteMtimeToleranceSeconds()) {
 					shouldOverwrite = true
 				}
 			}
@@ -0,0 +1,88 @@
+// Copyright © 2017 Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ste
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+)
+
+// deleteMovedSource deletes the source of a transfer that was copied successfully, for CopyJobPartOrderRequest.MoveSource.
+// A source that's already gone counts as deleted.
+func deleteMovedSource(jptm IJobPartTransferMgr) error {
+	info := jptm.Info()
+	if info.VersionID != "" || info.SnapshotID != "" {
+		return errors.New("versions and snapshots cannot be moved")
+	}
+
+	var err error
+	switch jptm.FromTo().From() {
+	case common.ELocation.Blob():
+		err = deleteMovedBlob(jptm, info)
+	case common.ELocation.BlobFS():
+		err = deleteMovedBlobFSFile(jptm, info)
+	case common.ELocation.File(), common.ELocation.FileNFS():
+		err = deleteMovedAzureFile(jptm, info)
+	default:
+		return fmt.Errorf("sources cannot be moved from %s", jptm.FromTo().From())
+	}
+
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func deleteMovedBlob(jptm IJobPartTransferMgr, info *TransferInfo) error {
+	s, err := jptm.SrcServiceClient().BlobServiceClient()
+	if err != nil {
+		return err
+	}
+	_, err = s.NewContainerClient(info.SrcContainer).NewBlobClient(info.SrcFilePath).Delete(jptm.Context(), nil)
+	return err
+}
+
+func deleteMovedBlobFSFile(jptm IJobPartTransferMgr, info *TransferInfo) error {
+	s, err := jptm.SrcServiceClient().DatalakeServiceClient()
+	if err != nil {
+		return err
+	}
+	_, err = s.NewFileSystemClient(info.SrcContainer).NewFileClient(info.SrcFilePath).Delete(jptm.Context(), nil)
+	return err
+}
+
+func deleteMovedAzureFile(jptm IJobPartTransferMgr, info *TransferInfo) error {
+	s, err := jptm.SrcServiceClient().FileServiceClient()
+	if err != nil {
+		return err
+	}
+	fileClient := s.NewShareClient(info.SrcContainer).NewRootDirectoryClient().NewFileClient(info.SrcFilePath)
+	return common.DoWithOverrideReadOnlyOnAzureFiles(jptm.Context(),
+		func() (interface{}, error) { return fileClient.Delete(jptm.Context(), nil) },
+		fileClient,
+		jptm.GetForceIfReadOnly())
+}
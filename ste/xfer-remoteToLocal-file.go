@@ -75,21 +75,21 @@ func remoteToLocal_file(jptm IJobPartTransferMgr, pacer pacer, df downloaderFact
 		jptm.ReportTransferDone()
 		return
 	}
-	// if the force Write flags is set to false or prompt
+	// if the force Write flags is set to false or prompt (or the check mode asks for it regardless)
 	// then check the file exists at the remote location
 	// if it does, react accordingly
-	if jptm.GetOverwriteOption() != common.EOverwriteOption.True() {
+	if jptm.ChecksDestination() {
 		dstProps, err := common.OSStat(info.Destination)
 		if err == nil {
 			// if the error is nil, then file exists locally
-			shouldOverwrite := false
+			shouldOverwrite := jptm.GetOverwriteOption() == common.EOverwriteOption.True()
 
 			// if necessary, prompt to confirm user's intent
 			if jptm.GetOverwriteOption() == common.EOverwriteOption.Prompt() {
 				shouldOverwrite = jptm.GetOverwritePrompter().ShouldOverwrite(info.Destination, common.EEntityType.File())
 			} else if jptm.GetOverwriteOption() == common.EOverwriteOption.IfSourceNewer() {
-				// only overwrite if source lmt is newer (after) the destination
-				if jptm.LastModifiedTime().After(dstProps.ModTime()) {
+				// only overwrite if source lmt is newer (after) the destination, beyond the tolerance
+				if common.IsSourceNewer(jptm.LastModifiedTime(), dstProps.ModTime(), jptm.GetOverwriteMtimeToleranceSeconds()) {
 					shouldOverwrite = true
 				}
 			}
@@ -39,6 +39,85 @@ func SetProperties(jptm IJobPartTransferMgr, _ pacer) {
 	jptm.ScheduleChunks(cf)
 }
 
+// SetDestinationProperties is used by metadata only jobs. Instead of copying the data, it applies the source's metadata
+// (or the job's metadata, if the source has none) and the requested tier to the existing destination blob.
+// The source's metadata is read through sipf, since it's usually fetched in the backend rather than enumerated.
+func SetDestinationProperties(jptm IJobPartTransferMgr, _ pacer, sipf sourceInfoProviderFactory) {
+	if jptm.WasCanceled() {
+		jptm.ReportTransferDone()
+		return
+	}
+
+	id := common.NewChunkID(jptm.Info().Destination, 0, 0)
+	cf := createChunkFunc(true, jptm, id, func() {
+		setDestinationPropertiesBlob(jptm, sipf)
+	})
+	jptm.ScheduleChunks(cf)
+}
+
+func setDestinationPropertiesBlob(jptm IJobPartTransferMgr, sipf sourceInfoProviderFactory) {
+	info := jptm.Info()
+	transferDone := func(status common.TransferStatus, err error) {
+		if status == common.ETransferStatus.Failed() {
+			jptm.LogError(info.Destination, "SET-PROPERTIES FAILED with error: ", err)
+		} else {
+			jptm.Log(common.LogInfo, fmt.Sprintf("SET-PROPERTIES SUCCESSFUL: %s", strings.Split(info.Destination, "?")[0]))
+		}
+
+		jptm.SetStatus(status)
+		jptm.ResetSourceSize() // no data is transferred, so the bytes transferred must stay 0
+		jptm.ReportTransferDone()
+	}
+
+	// folders have no blob of their own to set properties on
+	if info.IsFolderPropertiesTransfer() {
+		transferDone(common.ETransferStatus.Success(), nil)
+		return
+	}
+
+	bsc, err := jptm.DstServiceClient().BlobServiceClient()
+	if err != nil {
+		transferDone(common.ETransferStatus.Failed(), err)
+		return
+	}
+
+	dstBlobClient := bsc.NewContainerClient(info.DstContainer).NewBlobClient(info.DstFilePath)
+
+	// as in the S2S senders, the source info provider fetches the properties in the backend when asked to
+	srcInfoProvider, err := sipf(jptm)
+	if err != nil {
+		transferDone(common.ETransferStatus.Failed(), err)
+		return
+	}
+	srcProperties, err := srcInfoProvider.Properties()
+	if err != nil {
+		transferDone(common.ETransferStatus.Failed(), err)
+		return
+	}
+
+	metadata := srcProperties.SrcMetadata
+	if len(metadata) == 0 {
+		_, metadata, _, _ = jptm.ResourceDstData(nil)
+	}
+
+	if blockBlobTier, _ := jptm.BlobTiers(); blockBlobTier != common.EBlockBlobTier.None() {
+		rehydratePriority := info.RehydratePriority
+		_, err := dstBlobClient.SetTier(jptm.Context(), blockBlobTier.ToAccessTierType(),
+			&blob.SetTierOptions{RehydratePriority: &rehydratePriority})
+		if err != nil {
+			errorHandlerForXferSetProperties(err, jptm, transferDone)
+			return
+		}
+	}
+
+	if _, err := dstBlobClient.SetMetadata(jptm.Context(), metadata, nil); err != nil {
+		errorHandlerForXferSetProperties(err, jptm, transferDone)
+		return
+	}
+
+	transferDone(common.ETransferStatus.Success(), nil)
+}
+
 func setPropertiesBlob(jptm IJobPartTransferMgr) {
 	info := jptm.Info()
 	// Internal function which checks the transfer status and logs the msg respectively.
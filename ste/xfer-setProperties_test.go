@@ -0,0 +1,109 @@
+// Copyright © Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ste
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	blobservice "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// metadataOnlyTestJPTM points the destination at a test server, and stands in for the job's settings
+type metadataOnlyTestJPTM struct {
+	testJobPartTransferManager
+	dstServiceClient *common.ServiceClient
+	jobMetadata      common.Metadata
+}
+
+func (t *metadataOnlyTestJPTM) DstServiceClient() *common.ServiceClient {
+	return t.dstServiceClient
+}
+
+func (t *metadataOnlyTestJPTM) BlobTiers() (common.BlockBlobTier, common.PageBlobTier) {
+	return common.EBlockBlobTier.None(), common.EPageBlobTier.None()
+}
+
+func (t *metadataOnlyTestJPTM) ResetSourceSize() {}
+
+func (t *metadataOnlyTestJPTM) ResourceDstData(_ []byte) (common.ResourceHTTPHeaders, common.Metadata, common.BlobTags, common.CpkOptions) {
+	return common.ResourceHTTPHeaders{}, t.jobMetadata, nil, common.CpkOptions{}
+}
+
+// backendPropertiesProvider stands in for a source info provider that fetches the properties in the backend
+type backendPropertiesProvider struct {
+	ISourceInfoProvider
+	metadata common.Metadata
+}
+
+func (p backendPropertiesProvider) Properties() (*SrcProperties, error) {
+	return &SrcProperties{SrcMetadata: p.metadata}, nil
+}
+
+func TestSetDestinationPropertiesAppliesBackendMetadata(t *testing.T) {
+	a := assert.New(t)
+
+	var mu sync.Mutex
+	var setMetadataRequests []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Query().Get("comp") == "metadata" {
+			mu.Lock()
+			setMetadataRequests = append(setMetadataRequests, r.Header.Clone())
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	bsc, err := blobservice.NewClientWithNoCredential(server.URL+"/account", nil)
+	a.NoError(err)
+
+	// the enumerator didn't fetch the source's metadata, so it's only known once the provider is asked for it
+	jptm := &metadataOnlyTestJPTM{
+		testJobPartTransferManager: testJobPartTransferManager{
+			info: to.Ptr(TransferInfo{
+				Source:                    "https://bucket.s3.amazonaws.com/dir/file.txt",
+				Destination:               server.URL + "/account/container/dir/file.txt",
+				DstContainer:              "container",
+				DstFilePath:               "dir/file.txt",
+				EntityType:                common.EEntityType.File(),
+				S2SGetPropertiesInBackend: true,
+			}),
+			fromTo: common.EFromTo.S3Blob(),
+		},
+		dstServiceClient: common.NewServiceClient(bsc, nil, nil),
+		jobMetadata:      common.Metadata{},
+	}
+	sipf := func(IJobPartTransferMgr) (ISourceInfoProvider, error) {
+		return backendPropertiesProvider{metadata: common.Metadata{"origin": to.Ptr("s3")}}, nil
+	}
+
+	setDestinationPropertiesBlob(jptm, sipf)
+
+	a.Equal(common.ETransferStatus.Success(), jptm.status)
+	a.Len(setMetadataRequests, 1)
+	a.Equal("s3", setMetadataRequests[0].Get("x-ms-meta-origin"))
+}
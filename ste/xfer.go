@@ -78,7 +78,7 @@ func parameterizeSend(targetFunction newJobXferWithSenderFactory, sf senderFacto
 }
 
 // the xfer factory is generated based on the type of source and destination
-func computeJobXfer(fromTo common.FromTo, blobType common.BlobType) newJobXfer {
+func computeJobXfer(fromTo common.FromTo, blobType common.BlobType, metadataOnly bool) newJobXfer {
 
 	//local helper functions
 
@@ -147,6 +147,13 @@ func computeJobXfer(fromTo common.FromTo, blobType common.BlobType) newJobXfer {
 	}
 
 	// main computeJobXfer logic
+	if metadataOnly {
+		sipf := getSipFactory(fromTo.From())
+		return func(jptm IJobPartTransferMgr, pacer pacer) {
+			SetDestinationProperties(jptm, pacer, sipf)
+		}
+	}
+
 	switch fromTo {
 	case common.EFromTo.BlobTrash():
 		return DeleteBlob
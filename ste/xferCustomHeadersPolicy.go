@@ -0,0 +1,50 @@
+// Copyright © Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ste
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// CustomHeaders is the key to the http.Header set on the requests made with the context it's set in, by clients that
+// have the custom headers policy. Jobs whose orders have BlobTransferAttributes.CustomHeaders set them in their context.
+var CustomHeaders = customHeaders{}
+
+type customHeaders struct{}
+
+type customHeadersPolicy struct{}
+
+// NewCustomHeadersPolicy creates a policy setting the CustomHeaders of requests' context on them. Only destination
+// clients have it, so that the custom headers aren't sent with the reads and listings of the source.
+func NewCustomHeadersPolicy() policy.Policy {
+	return customHeadersPolicy{}
+}
+
+func (customHeadersPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if headers, ok := req.Raw().Context().Value(CustomHeaders).(http.Header); ok {
+		for name, values := range headers {
+			req.Raw().Header[name] = values
+		}
+	}
+	return req.Next()
+}
@@ -0,0 +1,44 @@
+package ste
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomHeadersPolicy(t *testing.T) {
+	a := assert.New(t)
+
+	var sent http.Header
+	newClient := func(perCallPolicies ...policy.Policy) *blob.Client {
+		c, err := blob.NewClientWithNoCredential("https://acct.blob.core.windows.net/ct/blob", &blob.ClientOptions{
+			ClientOptions: azcore.ClientOptions{
+				PerCallPolicies: perCallPolicies,
+				Transport: FunctionTransporter{
+					doFunc: func(req *http.Request) (*http.Response, error) {
+						sent = req.Header
+						return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+					},
+				},
+			},
+		})
+		a.NoError(err)
+		return c
+	}
+	ctx := context.WithValue(context.Background(), CustomHeaders, http.Header{"Cache-Control": {"no-cache"}})
+
+	// destination clients send the job's headers
+	_, _ = newClient(NewCustomHeadersPolicy()).GetProperties(ctx, nil)
+	a.Equal("no-cache", sent.Get("Cache-Control"))
+	_, _ = newClient(NewCustomHeadersPolicy()).GetProperties(context.Background(), nil)
+	a.Empty(sent.Get("Cache-Control"))
+
+	// the others don't, though they share the job's context
+	_, _ = newClient().GetProperties(ctx, nil)
+	a.Empty(sent.Get("Cache-Control"))
+}
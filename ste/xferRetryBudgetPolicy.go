@@ -0,0 +1,100 @@
+// Copyright © 2017 Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ste
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+var transferRetryBudgetContextKey = contextKey{"transferRetryBudget"}
+
+// transferRetryBudget caps the retries of all of a transfer's requests together, so that a file that keeps failing
+// can't hold up the job by retrying each of its chunks as often as the job's retry policy allows.
+type transferRetryBudget struct {
+	maxRetries     uint32
+	atomicFailures int64
+}
+
+// withTransferRetryBudget returns a context whose requests may be retried maxRetries times in total.
+// The transferRetryBudgetPolicy then fails the request that would go over it.
+func withTransferRetryBudget(ctx context.Context, maxRetries uint32) context.Context {
+	return context.WithValue(ctx, transferRetryBudgetContextKey, &transferRetryBudget{maxRetries: maxRetries})
+}
+
+// transferRetriesExhaustedError is returned in place of the failure that would have taken a transfer over its retry budget.
+// It stops the retry policy from retrying the request, and marks the transfer's failure as such.
+type transferRetriesExhaustedError struct {
+	maxRetries uint32
+	cause      error
+}
+
+func (e *transferRetriesExhaustedError) Error() string {
+	return fmt.Sprintf("the transfer has used up its %d retries, the last attempt failed with: %v", e.maxRetries, e.cause)
+}
+
+func (e *transferRetriesExhaustedError) Unwrap() error {
+	return e.cause
+}
+
+// NonRetriable tells the retry policy not to retry the request.
+func (e *transferRetriesExhaustedError) NonRetriable() {}
+
+type transferRetryBudgetPolicy struct {
+}
+
+func newTransferRetryBudgetPolicy() policy.Policy {
+	return &transferRetryBudgetPolicy{}
+}
+
+func (p *transferRetryBudgetPolicy) Do(req *policy.Request) (*http.Response, error) {
+	response, err := req.Next() // Make the request
+
+	budget, ok := req.Raw().Context().Value(transferRetryBudgetContextKey).(*transferRetryBudget)
+	if !ok || !isRetriedFailure(response, err) {
+		return response, err
+	}
+
+	// every failure the retry policy retries costs one retry
+	if failures := atomic.AddInt64(&budget.atomicFailures, 1); failures > int64(budget.maxRetries) {
+		if err == nil {
+			// keep the response's status, so that the transfer's failure still reports it
+			err = azruntime.NewResponseError(response)
+		}
+		return response, &transferRetriesExhaustedError{maxRetries: budget.maxRetries, cause: err}
+	}
+	return response, err
+}
+
+// isRetriedFailure reports whether the retry policy retries a request that ended with response and err,
+// going by the same rules: the custom retry codes, if there are any, else azcore's defaults.
+func isRetriedFailure(response *http.Response, err error) bool {
+	if shouldRetry := GetShouldRetry(nil); shouldRetry != nil {
+		return shouldRetry(response, err)
+	}
+	return err != nil || azruntime.HasStatusCode(response, http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout)
+}
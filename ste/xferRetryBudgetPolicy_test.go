@@ -0,0 +1,60 @@
+package ste
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferRetryBudgetPolicy(t *testing.T) {
+	a := assert.New(t)
+
+	var hits int64
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		res.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	pl := runtime.NewPipeline("", "",
+		runtime.PipelineOptions{PerRetry: []policy.Policy{newTransferRetryBudgetPolicy()}},
+		&policy.ClientOptions{Transport: http.DefaultClient, Retry: policy.RetryOptions{MaxRetries: 5, RetryDelay: time.Millisecond, MaxRetryDelay: time.Millisecond}},
+	)
+	send := func(ctx context.Context) error {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, srv.URL)
+		a.NoError(err)
+		resp, err := pl.Do(req)
+		if err == nil && resp.StatusCode != http.StatusOK {
+			err = runtime.NewResponseError(resp)
+		}
+		return err
+	}
+
+	// without a budget, the job's retry policy decides
+	a.Error(send(context.Background()))
+	a.Equal(int64(6), atomic.SwapInt64(&hits, 0))
+
+	// the budget is shared by all the transfer's requests
+	ctx := withTransferRetryBudget(context.Background(), 2)
+	err := send(ctx)
+	var exhausted *transferRetriesExhaustedError
+	a.True(errors.As(err, &exhausted))
+	a.Equal(int64(3), atomic.SwapInt64(&hits, 0))
+	// the last attempt's status is kept
+	var respErr *azcore.ResponseError
+	a.True(errors.As(err, &respErr))
+	a.Equal(http.StatusServiceUnavailable, respErr.StatusCode)
+
+	err = send(ctx)
+	a.True(errors.As(err, &exhausted))
+	a.Equal(int64(1), atomic.SwapInt64(&hits, 0))
+}
@@ -0,0 +1,48 @@
+// Copyright © Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ste
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// UserAgentSuffix is the key to the suffix appended to the user agent of requests made with the context it's set in.
+// Jobs whose orders have a UserAgentSuffix set it in their context.
+var UserAgentSuffix = userAgentSuffix{}
+
+type userAgentSuffix struct{}
+
+type userAgentSuffixPolicy struct{}
+
+// newUserAgentSuffixPolicy creates a policy appending UserAgentSuffix to requests' user agent.
+// It's a per call policy, so that it runs after the telemetry policy has set the user agent.
+func newUserAgentSuffixPolicy() policy.Policy {
+	return userAgentSuffixPolicy{}
+}
+
+func (userAgentSuffixPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if suffix, ok := req.Raw().Context().Value(UserAgentSuffix).(string); ok && suffix != "" {
+		req.Raw().Header.Set("User-Agent", req.Raw().Header.Get("User-Agent")+" "+suffix)
+	}
+	return req.Next()
+}
@@ -0,0 +1,43 @@
+package ste
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAgentSuffixPolicy(t *testing.T) {
+	a := assert.New(t)
+
+	var userAgent string
+	c, err := blob.NewClientWithNoCredential("https://acct.blob.core.windows.net/ct/blob", &blob.ClientOptions{
+		ClientOptions: azcore.ClientOptions{
+			Telemetry:       policy.TelemetryOptions{ApplicationID: common.UserAgent},
+			PerCallPolicies: []policy.Policy{newUserAgentSuffixPolicy()},
+			Transport: FunctionTransporter{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					userAgent = req.Header.Get("User-Agent")
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+				},
+			},
+		},
+	})
+	a.NoError(err)
+
+	// the suffix comes last, after the default user agent
+	_, _ = c.GetProperties(context.WithValue(context.Background(), UserAgentSuffix, "team/data-platform"), nil)
+	a.True(strings.HasPrefix(userAgent, common.UserAgent+" "), userAgent)
+	a.True(strings.HasSuffix(userAgent, " team/data-platform"), userAgent)
+
+	// and without one, the user agent is left alone
+	_, _ = c.GetProperties(context.Background(), nil)
+	a.True(strings.HasPrefix(userAgent, common.UserAgent+" "), userAgent)
+	a.NotContains(userAgent, "team/data-platform")
+}
@@ -33,4 +33,12 @@ func CreateClientOptions(logger common.ILoggerResetable, srcCred *common.ScopedT
 	}, ste.NewAzcopyHTTPClient(frontEndMaxIdleConnectionsPerHost), logOptions, srcCred, reauthCred)
 }
 
+// CreateDestinationClientOptions creates the client options of a job's destination client. On top of those of
+// CreateClientOptions, its requests carry the job's custom headers; see ste.NewCustomHeadersPolicy.
+func CreateDestinationClientOptions(logger common.ILoggerResetable, srcCred *common.ScopedToken, reauthCred *common.ScopedAuthenticator) azcore.ClientOptions {
+	options := CreateClientOptions(logger, srcCred, reauthCred)
+	options.PerCallPolicies = append(options.PerCallPolicies, ste.NewCustomHeadersPolicy())
+	return options
+}
+
 const frontEndMaxIdleConnectionsPerHost = http.DefaultMaxIdleConnsPerHost
@@ -83,7 +83,7 @@ type StoredObject struct {
 	ContainerName string
 	// destination container name. Included in the processor after resolving container names.
 	DstContainerName string
-	// access tier, only included by blob traverser.
+	// access tier, only included by blob traverser, and by the S3 one when mapping storage classes to tiers.
 	BlobAccessTier blob.AccessTier
 	ArchiveStatus  blob.ArchiveStatus
 	// metadata, included in S2S transfers
@@ -371,6 +371,7 @@ type InitResourceTraverserOptions struct {
 
 	PreservePermissions common.PreservePermissionsOption // Blob, BlobFS
 	SymlinkHandling     common.SymlinkHandlingType       // Local
+	SymlinkLoopPolicy   common.SymlinkLoopPolicy         // Local
 	PermanentDelete     common.PermanentDeleteOption     // Blob, BlobFS
 	SyncHashType        common.SyncHashType              // Local
 	TrailingDotOption   common.TrailingDotOption         // Files
@@ -378,6 +379,9 @@ type InitResourceTraverserOptions struct {
 	Recursive               bool // All resources
 	GetPropertiesInFrontend bool // Files, GCP, S3
 	IncludeDirectoryStubs   bool // Blob, BlobFS
+	IncludeDirMarkers       bool // S3
+	RequesterPays           bool // S3
+	MapStorageClassToTier   bool // S3
 	PreserveBlobTags        bool // Blob, BlobFS
 	StripTopDir             bool // Local
 
@@ -386,6 +390,12 @@ type InitResourceTraverserOptions struct {
 	HardlinkHandling  common.HardlinkHandlingType
 	FromTo            common.FromTo
 	IncludeRoot       bool
+
+	InventoryManifestURL string // S3, enumerates the bucket from an S3 Inventory report instead of listing it
+	S3Region             string // S3, signs the requests for this region when the URL names none
+
+	// S3TagFilter, when set, enumerates only the S3 objects whose tags it passes. Reading the tags costs a request per object.
+	S3TagFilter func(tags map[string]string) bool
 }
 
 func (o *InitResourceTraverserOptions) PerformChecks() error {
@@ -584,11 +594,18 @@ func InitResourceTraverser(resource common.ResourceString, resourceLocation comm
 				return nil, err
 			}
 		} else {
-			output, err = NewS3Traverser(resourceURL, ctx, opts)
-
+			s3Traverser, err := NewS3Traverser(resourceURL, ctx, opts)
 			if err != nil {
 				return nil, err
 			}
+
+			output = s3Traverser
+			if opts.InventoryManifestURL != "" {
+				output, err = newS3InventoryTraverser(s3Traverser, opts.InventoryManifestURL)
+				if err != nil {
+					return nil, err
+				}
+			}
 		}
 	case common.ELocation.GCP():
 		resourceURL, err := resource.FullURL()
@@ -782,7 +799,7 @@ func WarnStdoutAndScanningLog(toLog string) {
 
 func (e *CopyEnumerator) Enumerate() (err error) {
 	err = e.Traverser.Traverse(NoPreProccessor, e.ObjectDispatcher, e.Filters)
-	if err != nil {
+	if err != nil && !errors.Is(err, ErrTransferLimitReached) {
 		return
 	}
 
@@ -823,6 +840,10 @@ func passedFilters(filters []ObjectFilter, storedObject StoredObject) (bool, err
 // Basically, anywhere ProcessIfPassedFilters is called, additionally call getProcessingError.
 var IgnoredError = errors.New("FileIgnored")
 
+// ErrTransferLimitReached is returned by an ObjectProcessor to stop enumerating once the job has as many transfers as
+// it's allowed. The CopyEnumerator then finalizes the job with the transfers it has, instead of failing.
+var ErrTransferLimitReached = errors.New("the job has reached its limit of transfers")
+
 func getProcessingError(errin error) (ignored bool, err error) {
 	if errin == IgnoredError {
 		return true, nil
@@ -61,6 +61,49 @@ func (f *ExcludeBlobTypeFilter) DoesPass(object StoredObject) bool {
 }
 
 // excludeContainerFilter filters out container names that must be excluded
+// SkipObjectFilter leaves out the objects Skips reports, calling OnSkip for each so that they can be counted.
+// It's how CopyJobPartOrderRequest.SkipsObject is applied while enumerating.
+type SkipObjectFilter struct {
+	Skips  func(entityType common.EntityType, size int64) bool
+	OnSkip func()
+}
+
+func (f *SkipObjectFilter) DoesSupportThisOS() (msg string, supported bool) {
+	return "", true
+}
+
+func (f *SkipObjectFilter) AppliesOnlyToFiles() bool {
+	return true
+}
+
+func (f *SkipObjectFilter) DoesPass(object StoredObject) bool {
+	if !f.Skips(object.EntityType, object.Size) {
+		return true
+	}
+	if f.OnSkip != nil {
+		f.OnSkip()
+	}
+	return false
+}
+
+// MaxDepthFilter leaves out the objects nested more than MaxDepth levels below the source root; see common.KeyDepth.
+// It's how CopyJobPartOrderRequest.MaxDepth is applied while enumerating.
+type MaxDepthFilter struct {
+	MaxDepth int
+}
+
+func (f *MaxDepthFilter) DoesSupportThisOS() (msg string, supported bool) {
+	return "", true
+}
+
+func (f *MaxDepthFilter) AppliesOnlyToFiles() bool {
+	return false // folders deeper than the limit are left out too
+}
+
+func (f *MaxDepthFilter) DoesPass(object StoredObject) bool {
+	return common.KeyDepth(object.RelativePath, "", "/") <= f.MaxDepth
+}
+
 type excludeContainerFilter struct {
 	containerNamesList map[string]bool
 }
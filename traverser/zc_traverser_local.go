@@ -48,7 +48,9 @@ type localTraverser struct {
 	recursive       bool
 	stripTopDir     bool
 	symlinkHandling common.SymlinkHandlingType
-	appCtx          context.Context
+	// what to do when a followed symlink leads back to an enumerated directory
+	symlinkLoopPolicy common.SymlinkLoopPolicy
+	appCtx            context.Context
 	// a generic function to notify that a new stored object has been enumerated
 	incrementEnumerationCounter enumerationCounterFunc
 	errorChannel                chan<- ErrorFileInfo
@@ -214,6 +216,20 @@ func WalkWithSymlinks(appCtx context.Context,
 	hardlinkHandling common.HardlinkHandlingType,
 	incrementEnumerationCounter enumerationCounterFunc,
 	fromTo common.FromTo) (err error) {
+	return walkWithSymlinks(appCtx, fullPath, walkFunc, symlinkHandling, common.ESymlinkLoopPolicy.Skip(), errorChannel, hardlinkHandling, incrementEnumerationCounter, fromTo)
+}
+
+// walkWithSymlinks is WalkWithSymlinks, with symlinkLoopPolicy deciding what happens to followed symlinks that lead back
+// to a directory that was already walked.
+func walkWithSymlinks(appCtx context.Context,
+	fullPath string,
+	walkFunc filepath.WalkFunc,
+	symlinkHandling common.SymlinkHandlingType,
+	symlinkLoopPolicy common.SymlinkLoopPolicy,
+	errorChannel chan<- ErrorFileInfo,
+	hardlinkHandling common.HardlinkHandlingType,
+	incrementEnumerationCounter enumerationCounterFunc,
+	fromTo common.FromTo) (err error) {
 
 	// We want to re-queue symlinks up in their evaluated form because filepath.Walk doesn't evaluate them for us.
 	// So, what is the plan of attack?
@@ -238,6 +254,9 @@ func WalkWithSymlinks(appCtx context.Context,
 		seenPaths = &realSeenPathsRecorder{make(map[string]struct{})} // have to use the RAM if we are dealing with symlinks, to prevent cycles
 	}
 
+	// set to stop the walk when a symlink loop is an error
+	var loopErr error
+
 	for len(walkQueue) > 0 {
 		queueItem := walkQueue[0]
 		walkQueue = walkQueue[1:]
@@ -348,7 +367,13 @@ func WalkWithSymlinks(appCtx context.Context,
 						// enumerate the FOLDER now (since its presence in seenDirs will prevent its properties getting enumerated later)
 						return err
 					} else {
-						WarnStdoutAndScanningLog(fmt.Sprintf("Ignored already linked directory pointed at %s (link at %s)", result, common.GenerateFullPath(fullPath, computedRelativePath)))
+						switch symlinkLoopPolicy {
+						case common.ESymlinkLoopPolicy.Error():
+							loopErr = fmt.Errorf("the symlink at %s leads back to the already enumerated directory %s", common.GenerateFullPath(fullPath, computedRelativePath), result)
+							return loopErr
+						case common.ESymlinkLoopPolicy.Skip():
+							WarnStdoutAndScanningLog(fmt.Sprintf("Ignored already linked directory pointed at %s (link at %s)", result, common.GenerateFullPath(fullPath, computedRelativePath)))
+						}
 					}
 				} else {
 					// It's a symlink to a file and we handle cyclic symlinks.
@@ -408,6 +433,9 @@ func WalkWithSymlinks(appCtx context.Context,
 				}
 			}
 		})
+		if loopErr != nil {
+			return loopErr
+		}
 	}
 
 	return
@@ -806,7 +834,7 @@ func (t *localTraverser) Traverse(preprocessor objectMorpher, processor ObjectPr
 			}
 
 			// note: Walk includes root, so no need here to separately create StoredObject for root (as we do for other folder-aware sources)
-			return finalizer(WalkWithSymlinks(t.appCtx, t.fullPath, processFile, t.symlinkHandling, t.errorChannel, t.hardlinkHandling, t.incrementEnumerationCounter, t.fromTo))
+			return finalizer(walkWithSymlinks(t.appCtx, t.fullPath, processFile, t.symlinkHandling, t.symlinkLoopPolicy, t.errorChannel, t.hardlinkHandling, t.incrementEnumerationCounter, t.fromTo))
 		} else {
 			// if recursive is off, we only need to scan the files immediately under the fullPath
 			// We don't transfer any directory properties here, not even the root. (Because the root's
@@ -919,6 +947,7 @@ func NewLocalTraverser(fullPath string, ctx context.Context, opts InitResourceTr
 		fullPath:                    CleanLocalPath(fullPath),
 		recursive:                   opts.Recursive,
 		symlinkHandling:             opts.SymlinkHandling,
+		symlinkLoopPolicy:           opts.SymlinkLoopPolicy,
 		appCtx:                      ctx,
 		incrementEnumerationCounter: opts.IncrementEnumeration,
 		errorChannel:                opts.ErrorChannel,
@@ -1,10 +1,16 @@
 package traverser
 
 import (
+	"context"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
 )
 
 func TestCleanLocalPath(t *testing.T) {
@@ -49,3 +55,38 @@ func TestCleanLocalPathForWindows(t *testing.T) {
 		a.Equal(expected, CleanLocalPath(orig))
 	}
 }
+
+func TestWalkWithSymlinksLoopPolicy(t *testing.T) {
+	a := assert.New(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks needs privileges on Windows")
+	}
+
+	// root/dir/loop -> root/dir
+	root := t.TempDir()
+	a.NoError(os.MkdirAll(filepath.Join(root, "dir"), 0o755))
+	a.NoError(os.WriteFile(filepath.Join(root, "dir", "file.txt"), []byte("data"), 0o644))
+	a.NoError(os.Symlink(filepath.Join(root, "dir"), filepath.Join(root, "dir", "loop")))
+
+	walk := func(policy common.SymlinkLoopPolicy) ([]string, error) {
+		var mu sync.Mutex
+		var files []string
+		err := walkWithSymlinks(context.Background(), root, func(path string, fi os.FileInfo, err error) error {
+			if err == nil && !fi.IsDir() {
+				mu.Lock()
+				files = append(files, path)
+				mu.Unlock()
+			}
+			return err
+		}, common.ESymlinkHandlingType.Follow(), policy, nil, common.DefaultHardlinkHandlingType, nil, common.EFromTo.LocalBlob())
+		return files, err
+	}
+
+	// the loop is left out, and the files are enumerated once; Skip does the same, with a warning
+	files, err := walk(common.ESymlinkLoopPolicy.Ignore())
+	a.NoError(err)
+	a.Len(files, 1)
+
+	_, err = walk(common.ESymlinkLoopPolicy.Error())
+	a.ErrorContains(err, "leads back")
+}
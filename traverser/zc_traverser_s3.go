@@ -22,11 +22,13 @@ package traverser
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
 	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/minio/minio-go"
 
 	"github.com/Azure/azure-storage-azcopy/v10/common"
@@ -37,6 +39,16 @@ type s3Traverser struct {
 	ctx           context.Context
 	recursive     bool
 	getProperties bool
+	// includeDirMarkers emits S3's empty directory markers as folders, instead of skipping them
+	includeDirMarkers bool
+	// requesterPays sends x-amz-request-payer with every request that minio lets us add headers to
+	requesterPays bool
+	// mapStorageClassToTier sets the objects' access tiers to the ones their storage classes map to
+	mapStorageClassToTier bool
+	// tagFilter, when set, leaves out the objects whose tags it doesn't pass
+	tagFilter func(tags map[string]string) bool
+	// publicBucket is set when the bucket is read anonymously
+	publicBucket bool
 
 	s3URLParts common.S3URLParts
 	s3Client   *minio.Client
@@ -54,7 +66,7 @@ func (t *s3Traverser) IsDirectory(isSource bool) (bool, error) {
 		return isDirDirect, nil
 	}
 
-	_, err := t.s3Client.StatObject(t.s3URLParts.BucketName, t.s3URLParts.ObjectKey, minio.StatObjectOptions{})
+	_, err := t.s3Client.StatObject(t.s3URLParts.BucketName, t.s3URLParts.ObjectKey, t.statObjectOptions())
 
 	if err != nil {
 		return true, err
@@ -71,23 +83,14 @@ func (t *s3Traverser) Traverse(preprocessor objectMorpher, processor ObjectProce
 		return p(storedObject)
 	}
 
-	invalidAzureBlobName := func(objectKey string) bool {
-		/* S3 object name is invalid if it ends with period or
-		   one of virtual directories in path ends with period.
-		   This list is not exhaustive
-		*/
-		return strings.HasSuffix(objectKey, ".") ||
-			strings.Contains(objectKey, "./")
-	}
-	invalidNameErrorMsg := "Skipping S3 object %s, as it is not a valid Blob name. Rename the object and retry the transfer"
 	// Check if resource is a single object.
 	if t.s3URLParts.IsObjectSyntactically() && !t.s3URLParts.IsDirectorySyntactically() && !t.s3URLParts.IsBucketSyntactically() {
 		objectPath := strings.Split(t.s3URLParts.ObjectKey, "/")
 		objectName := objectPath[len(objectPath)-1]
 
-		oi, err := t.s3Client.StatObject(t.s3URLParts.BucketName, t.s3URLParts.ObjectKey, minio.StatObjectOptions{})
+		oi, err := t.s3Client.StatObject(t.s3URLParts.BucketName, t.s3URLParts.ObjectKey, t.statObjectOptions())
 		if invalidAzureBlobName(t.s3URLParts.ObjectKey) {
-			WarnStdoutAndScanningLog(fmt.Sprintf(invalidNameErrorMsg, t.s3URLParts.ObjectKey))
+			WarnStdoutAndScanningLog(fmt.Sprintf(invalidS3NameErrorMsg, t.s3URLParts.ObjectKey))
 			return common.EAzError.InvalidBlobName()
 		}
 
@@ -108,6 +111,11 @@ func (t *s3Traverser) Traverse(preprocessor objectMorpher, processor ObjectProce
 				NoBlobProps,
 				oie.NewCommonMetadata(),
 				t.s3URLParts.BucketName)
+			storedObject.BlobAccessTier = t.accessTier(oi.StorageClass)
+
+			if passes, err := t.passesTagFilter(t.s3URLParts.ObjectKey); err != nil || !passes {
+				return err
+			}
 
 			err = ProcessIfPassedFilters(
 				filters,
@@ -132,6 +140,12 @@ func (t *s3Traverser) Traverse(preprocessor objectMorpher, processor ObjectProce
 	searchPrefix := t.s3URLParts.ObjectKey
 
 	// It's a bucket or virtual directory.
+	// minio can't add headers to its list requests, and S3 refuses x-amz-request-payer unless it's signed,
+	// so a requester-pays bucket can't be listed; only single objects can be read from one.
+	if t.requesterPays {
+		return errors.New("cannot list objects of a requester-pays bucket, only single objects can be transferred from one")
+	}
+
 	for objectInfo := range t.s3Client.ListObjectsV2(t.s3URLParts.BucketName, searchPrefix, t.recursive, t.ctx.Done()) {
 		if objectInfo.Err != nil {
 			return fmt.Errorf("cannot list objects, %v", objectInfo.Err)
@@ -144,7 +158,7 @@ func (t *s3Traverser) Traverse(preprocessor objectMorpher, processor ObjectProce
 
 		if invalidAzureBlobName(objectInfo.Key) {
 			//Throw a warning on console and continue
-			WarnStdoutAndScanningLog(fmt.Sprintf(invalidNameErrorMsg, objectInfo.Key))
+			WarnStdoutAndScanningLog(fmt.Sprintf(invalidS3NameErrorMsg, objectInfo.Key))
 			continue
 		}
 
@@ -154,16 +168,30 @@ func (t *s3Traverser) Traverse(preprocessor objectMorpher, processor ObjectProce
 		// re-join the unescaped path.
 		relativePath := strings.TrimPrefix(objectInfo.Key, searchPrefix)
 
+		entityType := common.EEntityType.File()
 		if strings.HasSuffix(relativePath, "/") {
 			// If a file has a suffix of /, it's still treated as a folder.
-			// Thus, akin to the old code. skip it.
-			continue
+			// Thus, akin to the old code. skip it, unless it's an empty directory marker we were asked to preserve.
+			relativePath = strings.TrimSuffix(relativePath, "/")
+			if !t.includeDirMarkers || relativePath == "" || !common.IsEmptyDirMarker(objectInfo.Key, objectInfo.Size) {
+				continue
+			}
+			entityType = common.EEntityType.Folder()
+			objectName = objectPath[len(objectPath)-2]
+		}
+
+		if entityType == common.EEntityType.File() {
+			if passes, err := t.passesTagFilter(objectInfo.Key); err != nil {
+				return err
+			} else if !passes {
+				continue
+			}
 		}
 
 		// default to empty props, but retrieve real ones if required
 		oie := common.ObjectInfoExtension{ObjectInfo: minio.ObjectInfo{}}
 		if t.getProperties {
-			oi, err := t.s3Client.StatObject(t.s3URLParts.BucketName, objectInfo.Key, minio.StatObjectOptions{})
+			oi, err := t.s3Client.StatObject(t.s3URLParts.BucketName, objectInfo.Key, t.statObjectOptions())
 			if err != nil {
 				return err
 			}
@@ -173,13 +201,14 @@ func (t *s3Traverser) Traverse(preprocessor objectMorpher, processor ObjectProce
 			preprocessor,
 			objectName,
 			relativePath,
-			common.EEntityType.File(),
+			entityType,
 			objectInfo.LastModified,
 			objectInfo.Size,
 			&oie,
 			NoBlobProps,
 			oie.NewCommonMetadata(),
 			t.s3URLParts.BucketName)
+		storedObject.BlobAccessTier = t.accessTier(objectInfo.StorageClass)
 
 		err = ProcessIfPassedFilters(filters,
 			storedObject,
@@ -192,8 +221,57 @@ func (t *s3Traverser) Traverse(preprocessor objectMorpher, processor ObjectProce
 	return
 }
 
+const invalidS3NameErrorMsg = "Skipping S3 object %s, as it is not a valid Blob name. Rename the object and retry the transfer"
+
+func invalidAzureBlobName(objectKey string) bool {
+	/* S3 object name is invalid if it ends with period or
+	   one of virtual directories in path ends with period.
+	   This list is not exhaustive
+	*/
+	return strings.HasSuffix(objectKey, ".") ||
+		strings.Contains(objectKey, "./")
+}
+
+// accessTier returns the access tier an object of the given storage class is to get, if storage classes are mapped to tiers.
+func (t *s3Traverser) accessTier(storageClass string) blob.AccessTier {
+	if !t.mapStorageClassToTier {
+		return ""
+	}
+	if storageClass == "" {
+		storageClass = "STANDARD" // stats leave the class out for standard objects
+	}
+	tier, _ := common.StorageClassToBlobTier(storageClass)
+	return blob.AccessTier(tier)
+}
+
+// passesTagFilter reports whether the object with the given key passes the tag filter, if there's one.
+// The object's tags are only read when there is, since that's a request per object.
+func (t *s3Traverser) passesTagFilter(key string) (bool, error) {
+	if t.tagFilter == nil {
+		return true, nil
+	}
+	object := t.s3URLParts.BucketRoot()
+	object.ObjectKey = key
+	tags, err := common.GetS3ObjectTags(t.ctx, t.s3Client, object, t.requesterPays, t.publicBucket)
+	if err != nil {
+		return false, fmt.Errorf("cannot read the tags of %s to filter by them, %w", key, err)
+	}
+	return t.tagFilter(tags), nil
+}
+
+// statObjectOptions returns the options every stat of an object must carry.
+func (t *s3Traverser) statObjectOptions() minio.StatObjectOptions {
+	options := minio.StatObjectOptions{}
+	if t.requesterPays {
+		options.Set("x-amz-request-payer", "requester")
+	}
+	return options
+}
+
 func NewS3Traverser(rawURL *url.URL, ctx context.Context, opts InitResourceTraverserOptions) (t *s3Traverser, err error) {
 	t = &s3Traverser{rawURL: rawURL, ctx: ctx, recursive: opts.Recursive, getProperties: opts.GetPropertiesInFrontend,
+		includeDirMarkers: opts.IncludeDirMarkers, requesterPays: opts.RequesterPays, mapStorageClassToTier: opts.MapStorageClassToTier,
+		tagFilter: opts.S3TagFilter, publicBucket: opts.CredentialType == common.ECredentialType.S3PublicBucket(),
 		incrementEnumerationCounter: opts.IncrementEnumeration}
 
 	// initialize S3 client and URL parts
@@ -211,8 +289,9 @@ func NewS3Traverser(rawURL *url.URL, ctx context.Context, opts InitResourceTrave
 	t.s3Client, err = common.CreateS3Client(t.ctx, common.CredentialInfo{
 		CredentialType: opts.CredentialType,
 		S3CredentialInfo: common.S3CredentialInfo{
-			Endpoint: t.s3URLParts.Endpoint,
-			Region:   t.s3URLParts.Region,
+			Endpoint:  t.s3URLParts.Endpoint,
+			Region:    common.ResolveS3Region(t.s3URLParts, opts.S3Region),
+			PathStyle: t.s3URLParts.RequiresPathStyle(),
 		},
 	}, common.AzcopyScanningLogger)
 
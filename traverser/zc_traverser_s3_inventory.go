@@ -0,0 +1,238 @@
+// Copyright © 2019 Microsoft <wastore@microsoft.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package traverser
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+)
+
+// s3InventoryTraverser enumerates an S3 bucket from one of its S3 Inventory reports, instead of listing it.
+// Only the objects under the source's prefix are enumerated, and only their current versions.
+// The report's files are read with the source's client, so they must be reachable through the same endpoint.
+type s3InventoryTraverser struct {
+	*s3Traverser
+	manifestURLParts common.S3URLParts
+}
+
+// s3InventoryManifest holds the parts of an S3 Inventory report's manifest.json that enumeration needs.
+type s3InventoryManifest struct {
+	SourceBucket string `json:"sourceBucket"`
+	FileFormat   string `json:"fileFormat"`
+	FileSchema   string `json:"fileSchema"`
+	Files        []struct {
+		Key string `json:"key"`
+	} `json:"files"`
+}
+
+// s3InventoryEntry is an object listed by an S3 Inventory report.
+type s3InventoryEntry struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	StorageClass string
+}
+
+func (t *s3InventoryTraverser) Traverse(preprocessor objectMorpher, processor ObjectProcessor, filters []ObjectFilter) error {
+	manifestBody, err := t.getObject(t.manifestURLParts.ObjectKey)
+	if err != nil {
+		return fmt.Errorf("cannot read the inventory manifest, %w", err)
+	}
+	manifest, err := parseS3InventoryManifest(manifestBody)
+	_ = manifestBody.Close()
+	if err != nil {
+		return err
+	}
+	if manifest.SourceBucket != t.s3URLParts.BucketName {
+		return fmt.Errorf("the inventory manifest is for the bucket %s, not %s", manifest.SourceBucket, t.s3URLParts.BucketName)
+	}
+
+	searchPrefix := t.s3URLParts.ObjectKey
+	if searchPrefix != "" && !strings.HasSuffix(searchPrefix, "/") {
+		searchPrefix += "/"
+	}
+
+	for _, file := range manifest.Files {
+		err = t.traverseInventoryFile(file.Key, manifest, searchPrefix, preprocessor, processor, filters)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *s3InventoryTraverser) traverseInventoryFile(key string, manifest s3InventoryManifest, searchPrefix string,
+	preprocessor objectMorpher, processor ObjectProcessor, filters []ObjectFilter) error {
+	body, err := t.getObject(key)
+	if err != nil {
+		return fmt.Errorf("cannot read the inventory file %s, %w", key, err)
+	}
+	defer body.Close()
+
+	var reader io.Reader = body
+	if strings.HasSuffix(key, ".gz") {
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			return fmt.Errorf("cannot decompress the inventory file %s, %w", key, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	return readS3InventoryCSV(reader, manifest.FileSchema, manifest.SourceBucket, func(entry s3InventoryEntry) error {
+		relativePath, ok := strings.CutPrefix(entry.Key, searchPrefix)
+		// folders, and the objects in sub-directories of a non-recursive source, aren't transferred
+		if !ok || relativePath == "" || strings.HasSuffix(relativePath, "/") || (!t.recursive && strings.Contains(relativePath, "/")) {
+			return nil
+		}
+		if invalidAzureBlobName(entry.Key) {
+			WarnStdoutAndScanningLog(fmt.Sprintf(invalidS3NameErrorMsg, entry.Key))
+			return nil
+		}
+		if passes, err := t.passesTagFilter(entry.Key); err != nil || !passes {
+			return err
+		}
+
+		oie := common.ObjectInfoExtension{ObjectInfo: minio.ObjectInfo{}}
+		if t.getProperties {
+			oi, err := t.s3Client.StatObject(t.s3URLParts.BucketName, entry.Key, t.statObjectOptions())
+			if err != nil {
+				return err
+			}
+			oie = common.ObjectInfoExtension{ObjectInfo: oi}
+		}
+		storedObject := NewStoredObject(
+			preprocessor,
+			relativePath[strings.LastIndex(relativePath, "/")+1:],
+			relativePath,
+			common.EEntityType.File(),
+			entry.LastModified,
+			entry.Size,
+			&oie,
+			NoBlobProps,
+			oie.NewCommonMetadata(),
+			t.s3URLParts.BucketName)
+		storedObject.BlobAccessTier = t.accessTier(entry.StorageClass)
+
+		t.incrementEnumerationCounter(storedObject.EntityType, common.SymlinkHandlingType(0), common.DefaultHardlinkHandlingType)
+		_, err := getProcessingError(ProcessIfPassedFilters(filters, storedObject, processor))
+		return err
+	})
+}
+
+func (t *s3InventoryTraverser) getObject(key string) (*minio.Object, error) {
+	options := minio.GetObjectOptions{}
+	if t.requesterPays {
+		options.Set("x-amz-request-payer", "requester")
+	}
+	return t.s3Client.GetObjectWithContext(t.ctx, t.manifestURLParts.BucketName, key, options)
+}
+
+// parseS3InventoryManifest reads an S3 Inventory report's manifest.json. Only CSV reports can be enumerated.
+func parseS3InventoryManifest(r io.Reader) (s3InventoryManifest, error) {
+	var manifest s3InventoryManifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return s3InventoryManifest{}, fmt.Errorf("cannot parse the inventory manifest, %w", err)
+	}
+	if !strings.EqualFold(manifest.FileFormat, "CSV") {
+		return s3InventoryManifest{}, fmt.Errorf("inventory reports in the %s format are not supported, only CSV ones are", manifest.FileFormat)
+	}
+	return manifest, nil
+}
+
+// readS3InventoryCSV calls emit for each current object of the bucket listed by an S3 Inventory CSV file.
+// The file has no header; its columns are those of the manifest's fileSchema, e.g. "Bucket, Key, Size, LastModifiedDate".
+// Noncurrent versions and delete markers are left out, as are the rows of other buckets.
+func readS3InventoryCSV(r io.Reader, fileSchema, bucket string, emit func(s3InventoryEntry) error) error {
+	columns := map[string]int{}
+	for index, name := range strings.Split(fileSchema, ",") {
+		columns[strings.TrimSpace(name)] = index
+	}
+	if _, ok := columns["Key"]; !ok {
+		return errors.New("the inventory's file schema has no Key column")
+	}
+	field := func(record []string, name string) string {
+		if index, ok := columns[name]; ok && index < len(record) {
+			return record[index]
+		}
+		return ""
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(columns)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read the inventory file, %w", err)
+		}
+
+		if field(record, "Bucket") != "" && field(record, "Bucket") != bucket {
+			continue
+		}
+		if field(record, "IsLatest") == "false" || field(record, "IsDeleteMarker") == "true" {
+			continue
+		}
+
+		// keys are URL encoded in the reports
+		key, err := url.QueryUnescape(field(record, "Key"))
+		if err != nil {
+			return fmt.Errorf("the inventory lists the malformed key %q, %w", field(record, "Key"), err)
+		}
+		entry := s3InventoryEntry{Key: key, StorageClass: field(record, "StorageClass")}
+		if size := field(record, "Size"); size != "" {
+			if entry.Size, err = strconv.ParseInt(size, 10, 64); err != nil {
+				return fmt.Errorf("the inventory lists the size %q for %s, %w", size, key, err)
+			}
+		}
+		if lastModified := field(record, "LastModifiedDate"); lastModified != "" {
+			if entry.LastModified, err = time.Parse(time.RFC3339Nano, lastModified); err != nil {
+				return fmt.Errorf("the inventory lists the last modified time %q for %s, %w", lastModified, key, err)
+			}
+		}
+
+		if err = emit(entry); err != nil {
+			return err
+		}
+	}
+}
+
+func newS3InventoryTraverser(s3Traverser *s3Traverser, manifestURL string) (*s3InventoryTraverser, error) {
+	manifestURLParts, err := common.NewS3URLPartsFromString(manifestURL)
+	if err != nil {
+		return nil, err
+	}
+	return &s3InventoryTraverser{s3Traverser: s3Traverser, manifestURLParts: manifestURLParts}, nil
+}
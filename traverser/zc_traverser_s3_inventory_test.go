@@ -0,0 +1,72 @@
+package traverser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const s3InventoryFixture = `"bucket","dir/a.txt","","true","false","10","2024-01-02T03:04:05.000Z","STANDARD"
+"bucket","dir/b%20c.txt","","true","false","0","2024-01-02T03:04:05.000Z","GLACIER"
+"bucket","dir/old.txt","v1","false","false","5","2023-01-02T03:04:05.000Z","STANDARD"
+"bucket","dir/deleted.txt","v2","true","true","","2024-01-02T03:04:05.000Z",""
+"other","dir/a.txt","","true","false","10","2024-01-02T03:04:05.000Z","STANDARD"
+`
+
+const s3InventoryFixtureSchema = "Bucket, Key, VersionId, IsLatest, IsDeleteMarker, Size, LastModifiedDate, StorageClass"
+
+func TestReadS3InventoryCSV(t *testing.T) {
+	a := assert.New(t)
+
+	var entries []s3InventoryEntry
+	err := readS3InventoryCSV(strings.NewReader(s3InventoryFixture), s3InventoryFixtureSchema, "bucket", func(entry s3InventoryEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	a.NoError(err)
+
+	// only the bucket's current objects are listed, with their keys unescaped
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	a.Equal([]s3InventoryEntry{
+		{Key: "dir/a.txt", Size: 10, LastModified: lastModified, StorageClass: "STANDARD"},
+		{Key: "dir/b c.txt", Size: 0, LastModified: lastModified, StorageClass: "GLACIER"},
+	}, entries)
+
+	// the schema decides which columns are read
+	entries = nil
+	err = readS3InventoryCSV(strings.NewReader("\"bucket\",\"key\"\n"), "Bucket, Key", "bucket", func(entry s3InventoryEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	a.NoError(err)
+	a.Equal([]s3InventoryEntry{{Key: "key"}}, entries)
+
+	// rows that don't match the schema are refused
+	err = readS3InventoryCSV(strings.NewReader("\"bucket\",\"key\"\n"), s3InventoryFixtureSchema, "bucket", func(s3InventoryEntry) error { return nil })
+	a.Error(err)
+	err = readS3InventoryCSV(strings.NewReader(""), "Bucket, Size", "bucket", func(s3InventoryEntry) error { return nil })
+	a.ErrorContains(err, "Key")
+}
+
+func TestParseS3InventoryManifest(t *testing.T) {
+	a := assert.New(t)
+
+	manifest, err := parseS3InventoryManifest(strings.NewReader(`{
+		"sourceBucket": "bucket",
+		"destinationBucket": "arn:aws:s3:::inventory",
+		"fileFormat": "CSV",
+		"fileSchema": "Bucket, Key, Size",
+		"files": [{"key": "bucket/config/data/1.csv.gz", "size": 100}, {"key": "bucket/config/data/2.csv.gz", "size": 200}]
+	}`))
+	a.NoError(err)
+	a.Equal("bucket", manifest.SourceBucket)
+	a.Equal("Bucket, Key, Size", manifest.FileSchema)
+	a.Len(manifest.Files, 2)
+	a.Equal("bucket/config/data/2.csv.gz", manifest.Files[1].Key)
+
+	// ORC and Parquet reports can't be read
+	_, err = parseS3InventoryManifest(strings.NewReader(`{"sourceBucket": "bucket", "fileFormat": "ORC"}`))
+	a.ErrorContains(err, "ORC")
+}
@@ -98,7 +98,10 @@ func (t *s3ServiceTraverser) Traverse(preprocessor objectMorpher, processor Obje
 			Recursive: true,
 
 			GetPropertiesInFrontend: t.opts.GetPropertiesInFrontend,
+			RequesterPays:           t.opts.RequesterPays,
+			MapStorageClassToTier:   t.opts.MapStorageClassToTier,
 			IncrementEnumeration:    t.opts.IncrementEnumeration,
+			S3Region:                t.opts.S3Region,
 		})
 
 		if err != nil {
@@ -151,7 +154,9 @@ func NewS3ServiceTraverser(rawURL *url.URL, ctx context.Context, opts InitResour
 	t.s3Client, err = common.CreateS3Client(t.ctx, common.CredentialInfo{
 		CredentialType: common.ECredentialType.S3AccessKey(),
 		S3CredentialInfo: common.S3CredentialInfo{
-			Endpoint: t.s3URL.Endpoint,
+			Endpoint:  t.s3URL.Endpoint,
+			Region:    common.ResolveS3Region(t.s3URL, t.opts.S3Region),
+			PathStyle: t.s3URL.RequiresPathStyle(),
 		},
 	}, common.AzcopyScanningLogger)
 	return
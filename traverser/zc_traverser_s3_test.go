@@ -0,0 +1,55 @@
+package traverser
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/azure-storage-azcopy/v10/common"
+)
+
+func TestS3TraverserRequesterPays(t *testing.T) {
+	a := assert.New(t)
+
+	newTraverser := func(rawURL string) *s3Traverser {
+		u, err := url.Parse(rawURL)
+		a.NoError(err)
+		traverser, err := NewS3Traverser(u, context.Background(), InitResourceTraverserOptions{
+			CredentialType:       common.ECredentialType.S3PublicBucket(),
+			Recursive:            true,
+			RequesterPays:        true,
+			IncrementEnumeration: enumerationCounterFuncNoop,
+		})
+		a.NoError(err)
+		return traverser
+	}
+
+	// stats carry the header
+	traverser := newTraverser("https://bucket.s3.us-west-2.amazonaws.com/object")
+	a.Equal("requester", traverser.statObjectOptions().Header().Get("x-amz-request-payer"))
+
+	// listings can't, so they're refused before anything is sent
+	for _, rawURL := range []string{"https://bucket.s3.us-west-2.amazonaws.com", "https://bucket.s3.us-west-2.amazonaws.com/dir/"} {
+		err := newTraverser(rawURL).Traverse(NoPreProccessor, func(StoredObject) error { return nil }, nil)
+		a.ErrorContains(err, "requester-pays", rawURL)
+	}
+}
+
+func TestS3TraverserAccessTier(t *testing.T) {
+	a := assert.New(t)
+
+	traverser := &s3Traverser{mapStorageClassToTier: true}
+	a.Equal(blob.AccessTierArchive, traverser.accessTier("GLACIER"))
+	a.Equal(blob.AccessTierCool, traverser.accessTier("STANDARD_IA"))
+	// stats leave out the class of standard objects
+	a.Equal(blob.AccessTierHot, traverser.accessTier(""))
+	// unmapped classes get the destination's default
+	a.Equal(blob.AccessTier(""), traverser.accessTier("OUTPOSTS"))
+
+	// tiers are only set when asked for
+	traverser = &s3Traverser{}
+	a.Equal(blob.AccessTier(""), traverser.accessTier("GLACIER"))
+}